@@ -0,0 +1,147 @@
+package cpe
+
+import (
+	"fmt"
+)
+
+// Operator is a logical operator of LogicalTest.
+type Operator int
+
+const (
+	And = Operator(iota)
+	Or
+)
+
+func (o Operator) String() string {
+	switch o {
+	case And:
+		return "AND"
+	case Or:
+		return "OR"
+	}
+	return "unknown operator"
+}
+
+// Platform reprecents platform of NISTIR 7698, a description of a platform by an expression of names.
+type Platform struct {
+	ID          string
+	Title       string
+	LogicalTest *LogicalTest
+}
+
+// LogicalTest reprecents logical-test of NISTIR 7698.
+// It combines the results of LogicalTests, FactRefs and CheckFactRefs with Operator, and negates the result if Negate is true.
+// A fact-ref is true if the name matches one of the known instances, that is the name is a superset of or equal to it.
+type LogicalTest struct {
+	Operator      Operator
+	Negate        bool
+	LogicalTests  []*LogicalTest
+	FactRefs      []*Item
+	CheckFactRefs []*CheckFactRef
+}
+
+// CheckFactRef reprecents check-fact-ref of NISTIR 7698, a reference to a check which is evaluated by a checking system, e.g. OVAL.
+type CheckFactRef struct {
+	System string `xml:"system,attr"`
+	Href   string `xml:"href,attr"`
+	IDRef  string `xml:"id-ref,attr"`
+}
+
+// Checker evaluates a check-fact-ref.
+type Checker func(check *CheckFactRef) (bool, error)
+
+// Evaluate returns true if p applies to a system which has the known instances.
+func (p *Platform) Evaluate(instances []*Item) (bool, error) {
+	return p.EvaluateWithChecker(instances, nil)
+}
+
+// EvaluateWithChecker is Evaluate with checker to evaluate check-fact-refs.
+func (p *Platform) EvaluateWithChecker(instances []*Item, checker Checker) (bool, error) {
+	if p.LogicalTest == nil {
+		return false, fmt.Errorf("cpe:platform %q: %w", p.ID, ErrInvalidExpression)
+	}
+	return p.LogicalTest.EvaluateWithChecker(instances, checker)
+}
+
+// Evaluate returns true if t is true for the known instances, as NISTIR 7698 6.
+// Returns an error if the result is ERROR, e.g. a fact-ref is not matched but the relation to an instance is undefined.
+// An ERROR of an operand is ignored if the result is determined by the other operands,
+// e.g. AND with a FALSE operand is FALSE.  Check-fact-refs are ERROR, use EvaluateWithChecker to evaluate them.
+func (t *LogicalTest) Evaluate(instances []*Item) (bool, error) {
+	return t.EvaluateWithChecker(instances, nil)
+}
+
+// EvaluateWithChecker is Evaluate with checker to evaluate check-fact-refs.
+func (t *LogicalTest) EvaluateWithChecker(instances []*Item, checker Checker) (bool, error) {
+	if t.Operator != And && t.Operator != Or {
+		return false, fmt.Errorf("cpe:%v in logical-test: %w", t.Operator, ErrInvalidExpression)
+	}
+
+	// identity is the result if no operand determines it, TRUE for AND and FALSE for OR.
+	identity := t.Operator == And
+	var operandErr error
+	determines := func(r bool, err error) bool {
+		if err != nil {
+			if operandErr == nil {
+				operandErr = err
+			}
+			return false
+		}
+		return r != identity
+	}
+
+	determined := false
+	for _, test := range t.LogicalTests {
+		if determined = determines(test.EvaluateWithChecker(instances, checker)); determined {
+			break
+		}
+	}
+	for _, name := range t.FactRefs {
+		if determined {
+			break
+		}
+		determined = determines(evaluateFactRef(name, instances))
+	}
+	for _, check := range t.CheckFactRefs {
+		if determined {
+			break
+		}
+		if checker == nil {
+			determined = determines(false, fmt.Errorf("cpe:check-fact-ref %q of %q: %w", check.IDRef, check.System, ErrNoChecker))
+		} else {
+			determined = determines(checker(check))
+		}
+	}
+
+	result := identity
+	switch {
+	case determined:
+		result = !identity
+	case operandErr != nil:
+		return false, operandErr
+	}
+	return result != t.Negate, nil
+}
+
+// evaluateFactRef returns true if name matches one of instances.
+func evaluateFactRef(name *Item, instances []*Item) (bool, error) {
+	if name == nil {
+		return false, fmt.Errorf("cpe:nil fact-ref: %w", ErrInvalidExpression)
+	}
+
+	var undefined *Item
+	for _, instance := range instances {
+		switch Compare(name, instance).Relation {
+		case Superset, Equal:
+			return true, nil
+		case Undefined:
+			if undefined == nil {
+				undefined = instance
+			}
+		}
+	}
+	if undefined != nil {
+		return false, fmt.Errorf("cpe:fact-ref %s for %s: %w", name.Formatted(), undefined.Formatted(), ErrIndeterminate)
+	}
+	return false, nil
+}
@@ -0,0 +1,104 @@
+package cpe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogicalTestEvaluate(t *testing.T) {
+	// examples of NISTIR 7698 5.3.
+	solarisWithWeblogic := &Platform{
+		ID:    "123",
+		Title: "Sun Solaris 5.8 or 5.9 with BEA Weblogic 8.1 installed",
+		LogicalTest: &LogicalTest{
+			Operator: And,
+			LogicalTests: []*LogicalTest{{
+				Operator: Or,
+				FactRefs: mustParse(t, "cpe:/o:sun:solaris:5.8", "cpe:/o:sun:solaris:5.9"),
+			}},
+			FactRefs: mustParse(t, "cpe:/a:bea:weblogic:8.1"),
+		},
+	}
+	windowsWithOffice := &Platform{
+		ID:    "456",
+		Title: "Microsoft Windows XP with Office 2003 or 2007 installed",
+		LogicalTest: &LogicalTest{
+			Operator: And,
+			LogicalTests: []*LogicalTest{{
+				Operator: Or,
+				FactRefs: mustParse(t, "cpe:/a:microsoft:office:2003", "cpe:/a:microsoft:office:2007"),
+			}},
+			FactRefs: mustParse(t, "cpe:/o:microsoft:windows_xp"),
+		},
+	}
+	notWindows := &Platform{
+		ID: "789",
+		LogicalTest: &LogicalTest{
+			Operator: Or,
+			Negate:   true,
+			FactRefs: mustParse(t, "cpe:/o:microsoft:windows"),
+		},
+	}
+
+	type testcase struct {
+		platform  *Platform
+		instances []*Item
+		expect    bool
+	}
+	var cases = []testcase{
+		{solarisWithWeblogic, mustParse(t, "cpe:/o:sun:solaris:5.9", "cpe:/a:bea:weblogic:8.1"), true},
+		{solarisWithWeblogic, mustParse(t, "cpe:/o:sun:solaris:5.9", "cpe:/a:bea:weblogic:8.1:sp1"), true},
+		{solarisWithWeblogic, mustParse(t, "cpe:/o:sun:solaris:5.10", "cpe:/a:bea:weblogic:8.1"), false},
+		{solarisWithWeblogic, mustParse(t, "cpe:/o:sun:solaris:5.8"), false},
+		{solarisWithWeblogic, mustParse(t), false},
+		{windowsWithOffice, mustParse(t, "cpe:/o:microsoft:windows_xp::sp2", "cpe:/a:microsoft:office:2007"), true},
+		{windowsWithOffice, mustParse(t, "cpe:/o:microsoft:windows_xp::sp2", "cpe:/a:microsoft:office:2000"), false},
+		{notWindows, mustParse(t, "cpe:/o:redhat:enterprise_linux:5"), true},
+		{notWindows, mustParse(t, "cpe:/o:microsoft:windows:10"), false},
+	}
+
+	for i, c := range cases {
+		r, err := c.platform.Evaluate(c.instances)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, r, "%d", i)
+	}
+}
+
+func TestLogicalTestEvaluateError(t *testing.T) {
+	windows := mustParse(t, "cpe:/o:microsoft:windows_xp")[0]
+	linux := mustParse(t, "cpe:/o:redhat:enterprise_linux")[0]
+	// a known instance with wildcards makes fact-refs undefined.
+	instances := make([]*Item, 2)
+	instances[0], _ = NewItemFromWfn(`wfn:[part="o",vendor="microsoft",product="windows_*"]`)
+	instances[1], _ = NewItemFromWfn(`wfn:[part="a",vendor="microsoft",product="office"]`)
+
+	type testcase struct {
+		test   *LogicalTest
+		expect bool
+		err    error
+	}
+	var cases = []testcase{
+		{&LogicalTest{Operator: And, FactRefs: []*Item{windows}}, false, ErrIndeterminate},
+		{&LogicalTest{Operator: Or, Negate: true, FactRefs: []*Item{windows}}, false, ErrIndeterminate},
+		// an ERROR operand does not matter if the result is determined by the other operands.
+		{&LogicalTest{Operator: And, FactRefs: []*Item{windows, linux}}, false, nil},
+		{&LogicalTest{Operator: Or, LogicalTests: []*LogicalTest{{Operator: And, FactRefs: []*Item{windows}}}, FactRefs: []*Item{instances[1]}}, true, nil},
+		{&LogicalTest{Operator: Or, LogicalTests: []*LogicalTest{{Operator: And, FactRefs: []*Item{windows}}}, FactRefs: []*Item{linux}}, false, ErrIndeterminate},
+		{&LogicalTest{Operator: Or, FactRefs: []*Item{nil}}, false, ErrInvalidExpression},
+		{&LogicalTest{Operator: Operator(2)}, false, ErrInvalidExpression},
+		// operators without operands are their identity.
+		{&LogicalTest{Operator: And}, true, nil},
+		{&LogicalTest{Operator: Or}, false, nil},
+	}
+
+	for i, c := range cases {
+		r, err := c.test.Evaluate(instances)
+		assert.Equal(t, c.expect, r, "%d", i)
+		assert.True(t, errors.Is(err, c.err), "%d: %v", i, err)
+	}
+
+	_, err := (&Platform{ID: "empty"}).Evaluate(instances)
+	assert.True(t, errors.Is(err, ErrInvalidExpression))
+}
@@ -0,0 +1,140 @@
+package cpe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LanguageNamespace is the XML namespace of the CPE Applicability Language.
+const LanguageNamespace = "http://cpe.mitre.org/language/2.0"
+
+// PlatformSpecification reprecents platform-specification of NISTIR 7698.
+type PlatformSpecification struct {
+	Platforms []*Platform
+}
+
+type xmlPlatform struct {
+	ID          string          `xml:"id,attr"`
+	Titles      []string        `xml:"http://cpe.mitre.org/language/2.0 title"`
+	LogicalTest *xmlLogicalTest `xml:"http://cpe.mitre.org/language/2.0 logical-test"`
+}
+
+type xmlLogicalTest struct {
+	Operator      string           `xml:"operator,attr"`
+	Negate        string           `xml:"negate,attr"`
+	LogicalTests  []xmlLogicalTest `xml:"http://cpe.mitre.org/language/2.0 logical-test"`
+	FactRefs      []xmlFactRef     `xml:"http://cpe.mitre.org/language/2.0 fact-ref"`
+	CheckFactRefs []*CheckFactRef  `xml:"http://cpe.mitre.org/language/2.0 check-fact-ref"`
+}
+
+type xmlFactRef struct {
+	Name string `xml:"name,attr"`
+}
+
+// ReadPlatformSpecification reads platforms of cpe-lang:platform-specification elements in the XML document from r.
+// The element may be the root, or in another document such as a XCCDF benchmark.
+// Names of fact-refs are URIs or formatted strings.
+func ReadPlatformSpecification(r io.Reader) (*PlatformSpecification, error) {
+	spec := &PlatformSpecification{}
+	found := false
+
+	d := xml.NewDecoder(r)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != LanguageNamespace || start.Name.Local != "platform-specification" {
+			continue
+		}
+		found = true
+
+		var doc struct {
+			Platforms []xmlPlatform `xml:"http://cpe.mitre.org/language/2.0 platform"`
+		}
+		if err := d.DecodeElement(&doc, &start); err != nil {
+			return nil, err
+		}
+		for _, p := range doc.Platforms {
+			platform, err := p.platform()
+			if err != nil {
+				return nil, err
+			}
+			spec.Platforms = append(spec.Platforms, platform)
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("cpe:%w", ErrNoPlatformSpecification)
+	}
+	return spec, nil
+}
+
+func (p xmlPlatform) platform() (*Platform, error) {
+	if p.LogicalTest == nil {
+		return nil, fmt.Errorf("cpe:platform %q has no logical-test: %w", p.ID, ErrInvalidExpression)
+	}
+
+	test, err := p.LogicalTest.logicalTest()
+	if err != nil {
+		return nil, fmt.Errorf("cpe:platform %q: %w", p.ID, err)
+	}
+	platform := &Platform{
+		ID:          p.ID,
+		LogicalTest: test,
+	}
+	if len(p.Titles) > 0 {
+		platform.Title = strings.TrimSpace(p.Titles[0])
+	}
+	return platform, nil
+}
+
+func (t xmlLogicalTest) logicalTest() (*LogicalTest, error) {
+	test := &LogicalTest{
+		CheckFactRefs: t.CheckFactRefs,
+	}
+
+	switch strings.ToUpper(t.Operator) {
+	case "AND":
+		test.Operator = And
+	case "OR":
+		test.Operator = Or
+	default:
+		return nil, fmt.Errorf("cpe:operator %q: %w", t.Operator, ErrInvalidExpression)
+	}
+
+	// negate is xsd:boolean.
+	switch strings.ToLower(t.Negate) {
+	case "", "false", "0":
+	case "true", "1":
+		test.Negate = true
+	default:
+		return nil, fmt.Errorf("cpe:negate %q: %w", t.Negate, ErrInvalidExpression)
+	}
+
+	for _, child := range t.LogicalTests {
+		c, err := child.logicalTest()
+		if err != nil {
+			return nil, err
+		}
+		test.LogicalTests = append(test.LogicalTests, c)
+	}
+
+	for _, ref := range t.FactRefs {
+		item, binding, err := Parse(ref.Name)
+		if err == nil && binding == WfnBinding {
+			err = newParseError(WfnBinding, "", 0, ErrInvalidPrefix)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cpe:fact-ref %q: %w", ref.Name, err)
+		}
+		test.FactRefs = append(test.FactRefs, item)
+	}
+	return test, nil
+}
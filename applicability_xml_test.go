@@ -0,0 +1,136 @@
+package cpe
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readPlatformSpecification(t *testing.T, name string) *PlatformSpecification {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	spec, err := ReadPlatformSpecification(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestReadPlatformSpecification(t *testing.T) {
+	spec := readPlatformSpecification(t, "platform-specification.xml")
+	if !assert.Len(t, spec.Platforms, 3) {
+		return
+	}
+
+	p := spec.Platforms[0]
+	assert.Equal(t, "123", p.ID)
+	assert.Equal(t, "Sun Solaris 5.8 or 5.9 with BEA Weblogic 8.1 installed", p.Title)
+	assert.Equal(t, &LogicalTest{
+		Operator: And,
+		LogicalTests: []*LogicalTest{{
+			Operator: Or,
+			FactRefs: mustParse(t, "cpe:/o:sun:solaris:5.8", "cpe:/o:sun:solaris:5.9"),
+		}},
+		FactRefs: mustParse(t, "cpe:/a:bea:weblogic:8.1"),
+	}, p.LogicalTest)
+
+	p = spec.Platforms[2]
+	assert.True(t, p.LogicalTest.LogicalTests[0].Negate)
+	assert.Equal(t, []*CheckFactRef{{
+		System: "http://oval.mitre.org/XMLSchema/oval-definitions-5",
+		Href:   "oval.xml",
+		IDRef:  "oval:org.example:def:1",
+	}}, p.LogicalTest.CheckFactRefs)
+
+	type testcase struct {
+		platform  int
+		instances []*Item
+		expect    bool
+	}
+	var cases = []testcase{
+		{0, mustParse(t, "cpe:/o:sun:solaris:5.8", "cpe:/a:bea:weblogic:8.1:sp2"), true},
+		{0, mustParse(t, "cpe:/o:sun:solaris:5.10", "cpe:/a:bea:weblogic:8.1"), false},
+		{1, mustParse(t, "cpe:/o:microsoft:windows_xp", "cpe:/a:microsoft:office:2003"), true},
+		{1, mustParse(t, "cpe:/o:microsoft:windows_7", "cpe:/a:microsoft:office:2003"), false},
+	}
+	for i, c := range cases {
+		r, err := spec.Platforms[c.platform].Evaluate(c.instances)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, r, "%d", i)
+	}
+}
+
+func TestReadPlatformSpecificationCheckFactRef(t *testing.T) {
+	p := readPlatformSpecification(t, "platform-specification.xml").Platforms[2]
+	linux := mustParse(t, "cpe:/o:redhat:enterprise_linux:8")
+	windows := mustParse(t, "cpe:/o:microsoft:windows:10")
+
+	_, err := p.Evaluate(linux)
+	assert.True(t, errors.Is(err, ErrNoChecker))
+	// the check-fact-ref does not matter, as the other operand of AND is FALSE.
+	r, err := p.Evaluate(windows)
+	assert.Nil(t, err)
+	assert.False(t, r)
+
+	checked := []string{}
+	checker := func(check *CheckFactRef) (bool, error) {
+		checked = append(checked, check.IDRef)
+		return true, nil
+	}
+	r, err = p.EvaluateWithChecker(linux, checker)
+	assert.Nil(t, err)
+	assert.True(t, r)
+	assert.Equal(t, []string{"oval:org.example:def:1"}, checked)
+}
+
+func TestReadPlatformSpecificationXccdf(t *testing.T) {
+	spec := readPlatformSpecification(t, "xccdf-benchmark.xml")
+	if !assert.Len(t, spec.Platforms, 1) {
+		return
+	}
+	p := spec.Platforms[0]
+	assert.Equal(t, "cpe_platform_rhel", p.ID)
+	assert.Equal(t, "", p.Title)
+
+	r, err := p.Evaluate(mustParse(t, "cpe:/o:redhat:enterprise_linux:8::server"))
+	assert.Nil(t, err)
+	assert.True(t, r)
+	r, err = p.Evaluate(mustParse(t, "cpe:/o:redhat:enterprise_linux:6"))
+	assert.Nil(t, err)
+	assert.False(t, r)
+}
+
+func TestReadPlatformSpecificationError(t *testing.T) {
+	const header = `<platform-specification xmlns="http://cpe.mitre.org/language/2.0"><platform id="p">`
+	const footer = `</platform></platform-specification>`
+	type testcase struct {
+		input string
+		err   error
+	}
+	var cases = []testcase{
+		{`<logical-test operator="XOR"><fact-ref name="cpe:/a:vendor"/></logical-test>`, ErrInvalidExpression},
+		{`<logical-test operator="AND" negate="yes"><fact-ref name="cpe:/a:vendor"/></logical-test>`, ErrInvalidExpression},
+		{`<logical-test operator="AND"><logical-test operator="NOT"/></logical-test>`, ErrInvalidExpression},
+		{`<title>no logical-test</title>`, ErrInvalidExpression},
+		{`<logical-test operator="AND"><fact-ref name="cpe:/x:vendor"/></logical-test>`, ErrIllegalCharacter},
+		{`<logical-test operator="AND"><fact-ref name="wfn:[part=&quot;a&quot;]"/></logical-test>`, ErrInvalidPrefix},
+	}
+
+	for i, c := range cases {
+		_, err := ReadPlatformSpecification(strings.NewReader(header + c.input + footer))
+		assert.True(t, errors.Is(err, c.err), "%d: %v", i, err)
+	}
+
+	_, err := ReadPlatformSpecification(strings.NewReader(`<platform-specification xmlns="http://cpe.mitre.org/language/1.0"/>`))
+	assert.True(t, errors.Is(err, ErrNoPlatformSpecification))
+	_, err = ReadPlatformSpecification(strings.NewReader(`<platform-specification xmlns="http://cpe.mitre.org/language/2.0">`))
+	assert.NotNil(t, err)
+}
@@ -1,7 +1,6 @@
 package cpe
 
 import (
-	"regexp"
 	"strings"
 )
 
@@ -20,9 +19,13 @@ type Attribute interface {
 type PartAttr rune
 
 // StringAttr reprecents other than part attribute of cpe item.
+// A value is either a logical value (ANY or NA) or a sequence of literal characters
+// with optional wildcards ("*" or sequence of "?") at the beginning and end.
 type StringAttr struct {
-	raw  string
-	isNa bool
+	isNa    bool
+	prefix  string // unquoted "*" or "?"s at the beginning of the value
+	literal string // characters which are matched literally
+	suffix  string // unquoted "*" or "?"s at the end of the value
 }
 
 var (
@@ -82,15 +85,18 @@ func newPartAttrFromFmtEncoded(str string) PartAttr {
 	return PartNotSet
 }
 
+// String returns "a", "o" or "h".  Returns "*" if m is not set or invalid.
 func (m PartAttr) String() string {
 	if m.IsValid() {
 		return string(m)
-	} else {
-		panic("\"%v\" is not valid as part attribute.")
 	}
+	return "*"
 }
 
 func (m PartAttr) wfnEncoded() string {
+	if !m.IsValid() {
+		return "ANY"
+	}
 	return "\"" + m.String() + "\""
 }
 
@@ -99,6 +105,9 @@ func (m PartAttr) fmtString() string {
 }
 
 func (m PartAttr) urlEncoded() string {
+	if !m.IsValid() {
+		return ""
+	}
 	return m.String()
 }
 
@@ -111,6 +120,7 @@ func (m PartAttr) IsValid() bool {
 	}
 }
 
+// PartNotSet means ANY.
 func (m PartAttr) IsEmpty() bool {
 	return m == PartNotSet
 }
@@ -121,85 +131,101 @@ func (src PartAttr) Comparison(trg Attribute) Relation {
 		return Undefined
 	}
 
-	if !src.IsValid() || !trg_part.IsValid() {
+	if (!src.IsValid() && !src.IsEmpty()) || (!trg_part.IsValid() && !trg_part.IsEmpty()) {
 		return Undefined
 	}
 
 	if src == trg_part {
 		return Equal
+	} else if src.IsEmpty() {
+		return Superset
+	} else if trg_part.IsEmpty() {
+		return Subset
 	}
 
 	return Disjoint
 }
 
 // NewStringAttr returns attribute of item with str.
+// Leading and trailing "*" or "?" in str are treated as wildcards, and other characters are matched literally.
+// Use NewLiteralStringAttr to match "*" or "?" at the beginning or end literally.
 func NewStringAttr(str string) StringAttr {
-	return StringAttr{
-		raw: str,
+	if str == "*" {
+		return Any
 	}
-}
 
-func newStringAttrFromWfnEncoded(str string) StringAttr {
-	if str == "NA" {
-		return Na
-	} else if str == "ANY" {
-		return Any
+	body := strings.TrimLeft(str, "*?")
+	i := strings.IndexAny(body, "*?")
+	if i < 0 {
+		i = len(body)
 	}
 	return StringAttr{
-		raw: wfn_encoder.Decode(strings.TrimPrefix(strings.TrimSuffix(str, "\""), "\"")),
+		prefix:  str[:len(str)-len(body)],
+		literal: body[:i],
+		suffix:  body[i:],
 	}
 }
 
-func newStringAttrFromUriEncoded(str string) StringAttr {
-	if str == "-" {
-		return Na
-	} else if str == "" || str == "*" {
-		return Any
-	}
+// NewLiteralStringAttr returns attribute of item which matches str literally.
+func NewLiteralStringAttr(str string) StringAttr {
 	return StringAttr{
-		raw: url_encoder.Decode(str),
+		literal: str,
 	}
 }
 
-func newStringAttrFromFmtEncoded(str string) StringAttr {
-	if str == "-" {
-		return Na
-	} else if str == "*" {
-		return Any
-	}
-	return StringAttr{
-		raw: fmt_encoder.Decode(str),
-	}
+// IsLogical returns true if s is a logical value, ANY or NA.
+func (s StringAttr) IsLogical() bool {
+	return s == Any || s == Na
+}
+
+// HasWildcard returns true if s has unquoted "*" or "?" at the beginning or end.
+func (s StringAttr) HasWildcard() bool {
+	return s.prefix != "" || s.suffix != ""
+}
+
+// Literal returns characters of s which are matched literally, without wildcards.
+func (s StringAttr) Literal() string {
+	return s.literal
+}
+
+// Prefix returns the wildcard at the beginning of s.  It is empty, "*" or sequence of "?".
+func (s StringAttr) Prefix() string {
+	return s.prefix
+}
+
+// Suffix returns the wildcard at the end of s.  It is empty, "*" or sequence of "?".
+func (s StringAttr) Suffix() string {
+	return s.suffix
 }
 
 func (s StringAttr) String() string {
 	if s.isNa {
 		return "-"
-	} else if len(s.raw) == 0 {
+	} else if s.IsEmpty() {
 		return "*"
 	}
 
-	return s.raw
+	return s.prefix + s.literal + s.suffix
 }
 
 func (s StringAttr) wfnEncoded() string {
 	if s.isNa {
 		return "NA"
-	} else if len(s.raw) == 0 {
+	} else if s.IsEmpty() {
 		return "ANY"
 	}
 
-	return "\"" + wfn_encoder.Encode(s.raw) + "\""
+	return "\"" + s.prefix + wfn_encoder.Encode(s.literal) + s.suffix + "\""
 }
 
 func (s StringAttr) fmtString() string {
 	if s.isNa {
 		return "-"
-	} else if len(s.raw) == 0 {
+	} else if s.IsEmpty() {
 		return "*"
 	}
 
-	return fmt_encoder.Encode(s.raw)
+	return s.prefix + fmt_encoder.Encode(s.literal) + s.suffix
 }
 
 func (s StringAttr) urlEncoded() string {
@@ -208,134 +234,135 @@ func (s StringAttr) urlEncoded() string {
 	} else if s.isNa {
 		return "-"
 	}
-	return url_encoder.Encode(s.raw)
+	return url_special_encoder.Encode(s.prefix) + url_encoder.Encode(s.literal) + url_special_encoder.Encode(s.suffix)
 }
 
 // Empty StringAttr means ANY.
 func (s StringAttr) IsEmpty() bool {
-	return s.raw == "" && !s.isNa
+	return s == Any
 }
 
-var stringAttrIsValidRegExp = regexp.MustCompile("\\A(\\*|\\?+){0,1}[a-zA-Z0-9\\-_!\"#$%&'()+,./:;<=>@\\[\\]^`{}\\|~\\\\]+(\\*|\\?+){0,1}$")
-
 func (s StringAttr) IsValid() bool {
-	if s.isNa && len(s.raw) != 0 {
-		return false
+	if s.isNa {
+		return s == Na
 	}
 
-	if stringAttrIsValidRegExp.FindString(s.raw) != s.raw {
+	if !isSpecialChars(s.prefix) || !isSpecialChars(s.suffix) || s.isLoneAsterisk() {
 		return false
 	}
 
+	for i := 0; i < len(s.literal); i++ {
+		if !isAlnum(s.literal[i]) && !isPunct(s.literal[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
+// stringAttrBuilder builds a StringAttr from characters of a bound value one by one.
+type stringAttrBuilder struct {
+	attr     StringAttr
+	suffixAt int
+}
+
+// add appends c at offset at to the value.  special is true if c is an unquoted "*" or "?".
+// "*" and "?" are special only at the beginning or end of the value, so if they would be embedded,
+// add appends them as literals and returns their offset and false.
+func (b *stringAttrBuilder) add(c byte, special bool, at int) (int, bool) {
+	s := &b.attr
+	switch {
+	case special && s.literal == "" && isSpecialChars(s.prefix+string(c)):
+		s.prefix += string(c)
+	case special && s.literal != "" && isSpecialChars(s.suffix+string(c)):
+		if s.suffix == "" {
+			b.suffixAt = at
+		}
+		s.suffix += string(c)
+	case special:
+		s.literal += s.suffix + string(c)
+		s.suffix = ""
+		return at, false
+	case s.suffix != "":
+		s.literal += s.suffix + string(c)
+		s.suffix = ""
+		return b.suffixAt, false
+	default:
+		s.literal += string(c)
+	}
+	return 0, true
+}
+
+// isLoneAsterisk returns true if s is an unquoted "*" only, which is bound to formatted string as ANY.
+func (s StringAttr) isLoneAsterisk() bool {
+	return s == StringAttr{prefix: "*"}
+}
+
+// isSpecialChars returns true if str is empty, "*" or sequence of "?".
+func isSpecialChars(str string) bool {
+	return str == "*" || strings.Trim(str, "?") == ""
+}
+
+// Comparison compares src and trg as NISTIR 7696 6.  Values are compared case-insensitively.
 func (src StringAttr) Comparison(trg Attribute) Relation {
 	trg_str, ok := trg.(StringAttr)
 	if !ok {
 		return Undefined
 	}
+	return src.compare(trg_str, false)
+}
 
+func (src StringAttr) compare(trg_str StringAttr, caseSensitive bool) Relation {
 	if !src.IsValid() || !trg_str.IsValid() {
 		return Undefined
 	}
 
-	if src == Any {
-		if trg_str == Any {
-			return Equal
-		} else if trg_str == Na {
-			return Superset
-		} else if !trg_str.withWildCard() {
-			return Superset
-		}
-		return Undefined
+	if !caseSensitive {
+		src.literal = strings.ToLower(src.literal)
+		trg_str.literal = strings.ToLower(trg_str.literal)
 	}
 
-	if src == Na {
-		if trg_str == Any {
-			return Subset
-		} else if trg_str == Na {
-			return Equal
-		} else if !trg_str.withWildCard() {
-			return Disjoint
-		}
+	switch {
+	case trg_str.HasWildcard():
 		return Undefined
-	}
-
-	if src.withWildCard() {
-		if trg_str == Any {
-			return Subset
-		} else if trg_str == Na {
-			return Disjoint
-		} else if trg_str.withWildCard() {
-			return Undefined
-		} else if match_wildcard(src.raw, trg_str.raw) {
-			return Superset
-		}
-		return Disjoint
-	} else {
-		if trg_str == Any {
-			return Subset
-		} else if trg_str == Na {
-			return Disjoint
-		} else if trg_str.withWildCard() {
-			return Undefined
-		} else if trg_str.raw == src.raw {
-			return Equal
-		}
+	case src == trg_str:
+		return Equal
+	case src == Any:
+		return Superset
+	case trg_str == Any:
+		return Subset
+	case src == Na || trg_str == Na:
 		return Disjoint
 	}
-
-	return Undefined
-}
-
-func (m StringAttr) withWildCard() bool {
-	prefix, suffix := m.raw[0], m.raw[len(m.raw)-1]
-	return prefix == '*' || prefix == '?' || suffix == '*' || suffix == '?'
+	return compare_strings(src, trg_str.literal)
 }
 
-func match_wildcard(src, trg string) bool {
-	sufw, sufq, prew, preq := 0, 0, 0, 0
-	if strings.HasPrefix(src, "?") {
-		before := len(src)
-		src = strings.TrimLeft(src, "?")
-		preq = before - len(src)
-	}
-	if strings.HasPrefix(src, "*") {
-		src = strings.TrimPrefix(src, "*")
-		prew = 1
-	}
-	if strings.HasSuffix(src, "?") {
-		before := len(src)
-		src = strings.TrimRight(src, "?")
-		sufq = before - len(src)
+// compare_strings implements compare_strings of NISTIR 7696 6.2 for src and trg, a value without wildcards.
+// As the pseudo-code, a sequence of "?" matches up to as many characters as its length.
+// Quoted "*" and "?" are in src.literal, so they are matched literally.
+func compare_strings(src StringAttr, trg string) Relation {
+	begins, ends := len(src.prefix), len(src.suffix)
+	if src.prefix == "*" {
+		begins = -1
 	}
-	if strings.HasSuffix(src, "*") {
-		src = strings.TrimSuffix(src, "*")
-		sufw = 1
+	if src.suffix == "*" {
+		ends = -1
 	}
 
-	i := strings.Index(trg, src)
-	if prew != 0 {
-		if i != len(trg)-len(src)-sufq && sufw == 0 {
-			return false
-		}
-	}
-	if sufw != 0 {
-		if i != preq && prew == 0 {
-			return false
+	index := strings.Index(trg, src.literal)
+	for index >= 0 {
+		if index > 0 && begins != -1 && begins < index {
+			break
 		}
-	}
-	if preq != 0 {
-		if i != preq || (i != len(trg)-len(src)-sufq && sufw == 0) {
-			return false
+		leftover := len(trg) - index - len(src.literal)
+		if leftover <= 0 || ends == -1 || leftover <= ends {
+			return Superset
 		}
-	}
-	if sufq != 0 {
-		if i != len(trg)-sufq-len(src) || (i != preq && prew == 0) {
-			return false
+		next := strings.Index(trg[index+1:], src.literal)
+		if next < 0 {
+			break
 		}
+		index += 1 + next
 	}
-
-	return true
+	return Disjoint
 }
@@ -1,8 +1,10 @@
 package cpe
 
 import (
-	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewStringAttr(t *testing.T) {
@@ -20,6 +22,7 @@ func TestNewStringAttr(t *testing.T) {
 		{"**crosoft", false},
 		{"microso**", false},
 		{"mic**roso", false},
+		{"*", true},
 		// {"-microsoft", false}, // FIXME:this case must check to invalid
 	}
 
@@ -76,8 +79,10 @@ func TestNewStringAttrFromWcnEncoded(t *testing.T) {
 	}
 
 	for i, c := range cases {
-		sa := newStringAttrFromWfnEncoded(c.input)
-		assert.Equal(t, c.expect, sa.raw, "%d", i)
+		sa, n, err := unbindWfnValue(c.input)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, len(c.input), n, "%d", i)
+		assert.Equal(t, NewStringAttr(c.expect), sa, "%d", i)
 	}
 }
 
@@ -99,39 +104,125 @@ func TestNewPartAttrFromWcnEncoded(t *testing.T) {
 	}
 }
 
-func TestMatchWildcard(t *testing.T) {
+func TestCompareStrings(t *testing.T) {
 	type testcase struct {
 		input  string
 		value  string
-		expect bool
+		expect Relation
 	}
 	var cases = []testcase{
-		{"*123", "11123", true},
-		{"*123", "11123a", false},
-		{"123*", "12311", true},
-		{"123*", "112311", false},
-		{"??123", "11123", true},
-		{"??123", "1123", false},
-		{"??123", "11123a", false},
-		{"123??", "12311", true},
-		{"123??", "123111", false},
-		{"123??", "112311", false},
-		{"*123?", "111233", true},
-		{"*123*", "11123111", true},
-		{"?123?", "11231", true},
-		{"?123*", "112333", true},
-		{"??123*", "1112333", true},
-		{"*123??", "1112335", true},
-		{"*123??", "11123355", false},
-		{"??123*", "18112333", false},
+		{"*123", "11123", Superset},
+		{"*123", "11123a", Disjoint},
+		{"123*", "12311", Superset},
+		{"123*", "112311", Disjoint},
+		{"??123", "11123", Superset},
+		{"??123", "1123", Superset},
+		{"??123", "123", Superset},
+		{"??123", "111123", Disjoint},
+		{"??123", "11123a", Disjoint},
+		{"123??", "12311", Superset},
+		{"123??", "123111", Disjoint},
+		{"123??", "112311", Disjoint},
+		{"*123?", "111233", Superset},
+		{"*123*", "11123111", Superset},
+		{"?123?", "11231", Superset},
+		{"?123*", "112333", Superset},
+		{"?123", "1123123", Disjoint},
+		{"?123", "123123", Disjoint},
+		{"123?", "1231234", Disjoint},
+		{"*123?", "1231234", Superset},
+		{"??123*", "1112333", Superset},
+		{"*123??", "1112335", Superset},
+		{"*123??", "11123355", Disjoint},
+		{"??123*", "18112333", Disjoint},
+		{"123", "123", Superset},
+		{"123", "1234", Disjoint},
+		{"??", "1", Superset},
+		{"??", "123", Disjoint},
 	}
 
 	for i, c := range cases {
-		assert.Equal(t, c.expect, match_wildcard(c.input, c.value), "%d", i)
+		assert.Equal(t, c.expect, compare_strings(NewStringAttr(c.input), c.value), "%d", i)
+	}
+}
+
+// specCompareStrings is a transliteration of compare_strings in NISTIR 7696 6.2
+// for values without quoted characters.  "?"s which are counted in begins are not counted in ends again.
+func specCompareStrings(source, target string) Relation {
+	start, end, begins, ends := 0, len(source), 0, 0
+	if strings.HasPrefix(source, "*") {
+		start, begins = 1, -1
+	} else {
+		for start < len(source) && source[start] == '?' {
+			start++
+			begins++
+		}
+	}
+	if strings.HasSuffix(source, "*") {
+		end, ends = end-1, -1
+	} else {
+		for end > start && source[end-1] == '?' {
+			end--
+			ends++
+		}
+	}
+	source = source[start:end]
+
+	index, leftover := -1, len(target)
+	for leftover > 0 {
+		if index+1 > len(target) {
+			break
+		}
+		i := strings.Index(target[index+1:], source)
+		if i == -1 {
+			break
+		}
+		index += 1 + i
+		if index > 0 && begins != -1 && begins < index {
+			break
+		}
+		leftover = len(target) - index - len(source)
+		if leftover > 0 && ends != -1 && leftover > ends {
+			continue
+		}
+		return Superset
+	}
+	return Disjoint
+}
+
+// TestCompareStringsExhaustive compares compare_strings with the pseudo-code of the spec
+// for every short value with wildcards and every short target.
+func TestCompareStringsExhaustive(t *testing.T) {
+	words := func(n int) []string {
+		ws := []string{""}
+		for l, all := 0, []string{""}; l < n; l++ {
+			next := []string{}
+			for _, w := range all {
+				next = append(next, w+"1", w+"2")
+			}
+			ws, all = append(ws, next...), next
+		}
+		return ws
+	}
+	specials := []string{"", "*", "?", "??", "???"}
+
+	for _, prefix := range specials {
+		for _, suffix := range specials {
+			for _, literal := range words(3) {
+				src := StringAttr{prefix: prefix, literal: literal, suffix: suffix}
+				if !src.IsValid() || src == Any || (literal == "" && suffix != "") {
+					continue
+				}
+				for _, trg := range words(6)[1:] {
+					expect := specCompareStrings(prefix+literal+suffix, trg)
+					assert.Equal(t, expect, compare_strings(src, trg), "%s %s", src, trg)
+				}
+			}
+		}
 	}
 }
 
-func testComparition(t *testing.T) {
+func TestComparison(t *testing.T) {
 	type testcase struct {
 		input  Attribute
 		value  Attribute
@@ -144,6 +235,9 @@ func testComparition(t *testing.T) {
 		{NewStringAttr("9.*"), NewStringAttr("9.3.2"), Superset},
 		{Any, Na, Superset},
 		{NewStringAttr("PalmOS"), Na, Disjoint},
+		{NewStringAttr("Microsoft"), NewStringAttr("microsoft"), Equal},
+		{NewStringAttr("MICRO*"), NewStringAttr("microsoft"), Superset},
+		{NewStringAttr("?ICROSOFT"), NewStringAttr("Microsoft"), Superset},
 	}
 
 	for i, c := range cases {
@@ -158,3 +252,96 @@ func BenchmarkStringAttrComparison(b *testing.B) {
 		sa1.Comparison(sa2)
 	}
 }
+
+func TestStringAttrAccessors(t *testing.T) {
+	type testcase struct {
+		input    StringAttr
+		logical  bool
+		wildcard bool
+		prefix   string
+		literal  string
+		suffix   string
+	}
+	var cases = []testcase{
+		{Any, true, false, "", "", ""},
+		{Na, true, false, "", "", ""},
+		{NewStringAttr("microsoft"), false, false, "", "microsoft", ""},
+		{NewStringAttr("*soft"), false, true, "*", "soft", ""},
+		{NewStringAttr("8.??"), false, true, "", "8.", "??"},
+		{NewStringAttr("??8.*"), false, true, "??", "8.", "*"},
+		{NewLiteralStringAttr("*soft?"), false, false, "", "*soft?", ""},
+	}
+
+	for i, c := range cases {
+		assert.Equal(t, c.logical, c.input.IsLogical(), "%d", i)
+		assert.Equal(t, c.wildcard, c.input.HasWildcard(), "%d", i)
+		assert.Equal(t, c.prefix, c.input.Prefix(), "%d", i)
+		assert.Equal(t, c.literal, c.input.Literal(), "%d", i)
+		assert.Equal(t, c.suffix, c.input.Suffix(), "%d", i)
+	}
+}
+
+func TestStringAttrEncoded(t *testing.T) {
+	type testcase struct {
+		input StringAttr
+		wfn   string
+		uri   string
+		fmt   string
+	}
+	var cases = []testcase{
+		{NewStringAttr("8.*"), `"8\.*"`, `8.%02`, `8.*`},
+		{NewLiteralStringAttr("8.*"), `"8\.\*"`, `8.%2a`, `8.\*`},
+		{NewStringAttr(`??a\-b`), `"??a\\\-b"`, `%01%01a%5c-b`, `??a\\-b`},
+		{NewLiteralStringAttr(`100%25~`), `"100\%25\~"`, `100%2525%7e`, `100\%25\~`},
+	}
+
+	for i, c := range cases {
+		assert.Equal(t, c.wfn, c.input.wfnEncoded(), "%d", i)
+		assert.Equal(t, c.uri, c.input.urlEncoded(), "%d", i)
+		assert.Equal(t, c.fmt, c.input.fmtString(), "%d", i)
+	}
+}
+
+func TestPartAttr(t *testing.T) {
+	type testcase struct {
+		input PartAttr
+		str   string
+		wfn   string
+		uri   string
+		fmt   string
+	}
+	var cases = []testcase{
+		{Application, "a", `"a"`, "a", "a"},
+		{PartNotSet, "*", "ANY", "", "*"},
+		{PartAttr('z'), "*", "ANY", "", "*"},
+	}
+
+	for i, c := range cases {
+		assert.Equal(t, c.str, c.input.String(), "%d", i)
+		assert.Equal(t, c.wfn, c.input.wfnEncoded(), "%d", i)
+		assert.Equal(t, c.uri, c.input.urlEncoded(), "%d", i)
+		assert.Equal(t, c.fmt, c.input.fmtString(), "%d", i)
+	}
+}
+
+func TestPartAttrComparison(t *testing.T) {
+	type testcase struct {
+		input  PartAttr
+		value  Attribute
+		expect Relation
+	}
+	var cases = []testcase{
+		{Application, Application, Equal},
+		{Application, Hardware, Disjoint},
+		{PartNotSet, PartNotSet, Equal},
+		{PartNotSet, OperationgSystem, Superset},
+		{OperationgSystem, PartNotSet, Subset},
+		{PartAttr('z'), Application, Undefined},
+		{Application, PartAttr('z'), Undefined},
+		{Application, NewStringAttr("a"), Undefined},
+	}
+
+	for i, c := range cases {
+		assert.Equal(t, c.expect, c.input.Comparison(c.value), "%d", i)
+	}
+}
@@ -0,0 +1,125 @@
+package cpe
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Conformance tests with the examples of NISTIR 7695 and NISTIR 7696 in testdata/*.tsv.
+
+// readTestdata returns tab separated columns of each line in testdata/name.  Blank lines and comments are skipped.
+func readTestdata(t *testing.T, name string, columns int) [][]string {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rows := [][]string{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		row := strings.Split(line, "\t")
+		if len(row) != columns {
+			t.Fatalf("%s:%d: want %d columns, but %d", name, n, columns, len(row))
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+var conformanceRelations = map[string]Relation{
+	"DISJOINT":  Disjoint,
+	"EQUAL":     Equal,
+	"SUBSET":    Subset,
+	"SUPERSET":  Superset,
+	"UNDEFINED": Undefined,
+}
+
+func TestConformanceBindUri(t *testing.T) {
+	for i, row := range readTestdata(t, "naming-bind-uri.tsv", 2) {
+		item, err := NewItemFromWfn(row[0])
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, row[1], item.Uri(), "%d", i)
+		}
+	}
+}
+
+func TestConformanceBindFormattedString(t *testing.T) {
+	for i, row := range readTestdata(t, "naming-bind-fs.tsv", 2) {
+		item, err := NewItemFromWfn(row[0])
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, row[1], item.Formatted(), "%d", i)
+		}
+	}
+}
+
+func TestConformanceUnbindUri(t *testing.T) {
+	for i, row := range readTestdata(t, "naming-unbind-uri.tsv", 2) {
+		item, err := NewItemFromUri(row[0])
+		if row[1] == "ERROR" {
+			assert.Error(t, err, "%d", i)
+			continue
+		}
+		expect, err2 := NewItemFromWfn(row[1])
+		if assert.Nil(t, err, "%d", i) && assert.Nil(t, err2, "%d", i) {
+			assert.Equal(t, *expect, *item, "%d", i)
+		}
+	}
+}
+
+func TestConformanceUnbindFormattedString(t *testing.T) {
+	for i, row := range readTestdata(t, "naming-unbind-fs.tsv", 2) {
+		item, err := NewItemFromFormattedString(row[0])
+		if row[1] == "ERROR" {
+			assert.Error(t, err, "%d", i)
+			continue
+		}
+		expect, err2 := NewItemFromWfn(row[1])
+		if assert.Nil(t, err, "%d", i) && assert.Nil(t, err2, "%d", i) {
+			assert.Equal(t, *expect, *item, "%d", i)
+		}
+	}
+}
+
+func TestConformanceAttributeComparison(t *testing.T) {
+	for i, row := range readTestdata(t, "matching-attributes.tsv", 3) {
+		src, _, err := unbindWfnValue(row[0])
+		assert.Nil(t, err, "%d", i)
+		trg, _, err := unbindWfnValue(row[1])
+		assert.Nil(t, err, "%d", i)
+		expect, ok := conformanceRelations[row[2]]
+		if assert.True(t, ok, "%d", i) {
+			assert.Equal(t, expect, src.Comparison(trg), "%d: %s %s", i, row[0], row[1])
+		}
+	}
+}
+
+func TestConformanceNameComparison(t *testing.T) {
+	for i, row := range readTestdata(t, "matching-names.tsv", 3) {
+		src, err := NewItemFromWfn(row[0])
+		assert.Nil(t, err, "%d", i)
+		trg, err := NewItemFromWfn(row[1])
+		assert.Nil(t, err, "%d", i)
+
+		expect := map[string]bool{}
+		for _, r := range strings.Split(row[2], ",") {
+			expect[r] = true
+		}
+		assert.Equal(t, expect["DISJOINT"], CheckDisjoint(src, trg), "%d", i)
+		assert.Equal(t, expect["EQUAL"], CheckEqual(src, trg), "%d", i)
+		assert.Equal(t, expect["SUBSET"], CheckSubset(src, trg), "%d", i)
+		assert.Equal(t, expect["SUPERSET"], CheckSuperset(src, trg), "%d", i)
+	}
+}
@@ -0,0 +1,77 @@
+package cpe
+
+// Conversions between bindings as NISTIR 7695 6.  Each function returns lossy as true
+// if the result does not unbind to the same WFN as the source, e.g. an uppercase letter
+// in a URI, which is unbound as lowercase, or a value "-", which is unbound as NA.
+
+// ConvertURIToFS converts a URI binding to a formatted string binding.
+func ConvertURIToFS(uri string) (fs string, lossy bool, err error) {
+	item, err := NewItemFromUri(uri)
+	if err != nil {
+		return "", false, err
+	}
+	return convertToFS(item)
+}
+
+// ConvertFSToURI converts a formatted string binding to a URI binding.
+func ConvertFSToURI(fs string) (uri string, lossy bool, err error) {
+	item, err := NewItemFromFormattedString(fs)
+	if err != nil {
+		return "", false, err
+	}
+	return convertToURI(item)
+}
+
+// ConvertWFNToURI converts a WFN to a URI binding as bind_to_URI.
+func ConvertWFNToURI(wfn string) (uri string, lossy bool, err error) {
+	item, err := NewItemFromWfn(wfn)
+	if err != nil {
+		return "", false, err
+	}
+	return convertToURI(item)
+}
+
+// ConvertWFNToFS converts a WFN to a formatted string binding as bind_to_fs.
+func ConvertWFNToFS(wfn string) (fs string, lossy bool, err error) {
+	item, err := NewItemFromWfn(wfn)
+	if err != nil {
+		return "", false, err
+	}
+	return convertToFS(item)
+}
+
+// ConvertURIToWFN converts a URI binding to a WFN as unbind_URI.  The conversion is never lossy.
+func ConvertURIToWFN(uri string) (wfn string, err error) {
+	item, err := NewItemFromUri(uri)
+	if err != nil {
+		return "", err
+	}
+	return item.Wfn(), nil
+}
+
+// ConvertFSToWFN converts a formatted string binding to a WFN as unbind_fs.  The conversion is never lossy.
+func ConvertFSToWFN(fs string) (wfn string, err error) {
+	item, err := NewItemFromFormattedString(fs)
+	if err != nil {
+		return "", err
+	}
+	return item.Wfn(), nil
+}
+
+func convertToURI(item *Item) (string, bool, error) {
+	uri := item.Uri()
+	back, err := NewItemFromUri(uri)
+	if err != nil {
+		return "", false, err
+	}
+	return uri, *back != *item, nil
+}
+
+func convertToFS(item *Item) (string, bool, error) {
+	fs := item.Formatted()
+	back, err := NewItemFromFormattedString(fs)
+	if err != nil {
+		return "", false, err
+	}
+	return fs, *back != *item, nil
+}
@@ -0,0 +1,57 @@
+package cpe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	type testcase struct {
+		convert func(string) (string, bool, error)
+		input   string
+		expect  string
+		lossy   bool
+	}
+	var cases = []testcase{
+		{ConvertURIToFS, `cpe:/a:microsoft:internet_explorer:8.0.6001:beta`, `cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`, false},
+		{ConvertURIToFS, `cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~`, `cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:*`, false},
+		{ConvertURIToFS, `cpe:/a:Microsoft:internet_explorer:8.%02:sp%01`, `cpe:2.3:a:microsoft:internet_explorer:8.*:sp?:*:*:*:*:*:*`, false},
+		{ConvertURIToFS, `cpe:/a:foo~bar:big%24money::::en-us`, `cpe:2.3:a:foo\~bar:big\$money:*:*:*:en-us:*:*:*:*`, false},
+		{ConvertFSToURI, `cpe:2.3:a:hp:openview_network_manager:7.51:*:*:*:*:linux:*:*`, `cpe:/a:hp:openview_network_manager:7.51::~~~linux~~`, false},
+		{ConvertFSToURI, `cpe:2.3:a:foo\\bar:big\$money_2010:*:*:*:*:special:ipod_touch:80gb:*`, `cpe:/a:foo%5cbar:big%24money_2010:::~~special~ipod_touch~80gb~`, false},
+		{ConvertFSToURI, `cpe:2.3:a:microsoft:internet_explorer:8.\*:*:*:en:*:*:*:*`, `cpe:/a:microsoft:internet_explorer:8.%2a:::en`, false},
+		{ConvertFSToURI, `cpe:2.3:a:Microsoft:internet_explorer:*:*:*:*:*:*:*:*`, `cpe:/a:Microsoft:internet_explorer`, true},
+		{ConvertFSToURI, `cpe:2.3:a:foo:bar:\-:*:*:*:*:*:*:*`, `cpe:/a:foo:bar:-`, true},
+		{ConvertWFNToURI, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*",update="sp?"]`, `cpe:/a:microsoft:internet_explorer:8.%02:sp%01`, false},
+		{ConvertWFNToURI, `wfn:[part="a",vendor="hp",product="insight_diagnostics",version="7\.4\.0\.1570",update=NA,sw_edition="online",target_sw="win2003",target_hw="x64"]`, `cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~`, false},
+		{ConvertWFNToFS, `wfn:[part="a",vendor="hp",product="openview_network_manager",version="7\.51",update=NA,target_sw="linux"]`, `cpe:2.3:a:hp:openview_network_manager:7.51:-:*:*:*:linux:*:*`, false},
+		{ConvertWFNToFS, `wfn:[part="a",vendor="foo",product="bar",version="\-"]`, `cpe:2.3:a:foo:bar:-:*:*:*:*:*:*:*`, true},
+	}
+
+	for i, c := range cases {
+		actual, lossy, err := c.convert(c.input)
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, c.expect, actual, "%d", i)
+			assert.Equal(t, c.lossy, lossy, "%d", i)
+		}
+	}
+
+	_, _, err := ConvertURIToFS(`cpe:2.3:a:microsoft`)
+	assert.Error(t, err)
+	_, _, err = ConvertFSToURI(`cpe:/a:microsoft`)
+	assert.Error(t, err)
+}
+
+func TestConvertToWFN(t *testing.T) {
+	wfn, err := ConvertURIToWFN(`cpe:/a:microsoft:internet_explorer:8.%2a:sp%3f`)
+	assert.Nil(t, err)
+	assert.Equal(t, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.\*",update="sp\?"]`, wfn)
+
+	wfn, err = ConvertFSToWFN(`cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`)
+	assert.Nil(t, err)
+	assert.Equal(t, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta"]`, wfn)
+
+	_, err = ConvertFSToWFN(`cpe:/a:microsoft`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package cpe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeprecationStep is a step of a deprecation chain, from a deprecated entry to a name which deprecates it.
+type DeprecationStep struct {
+	From *DictionaryEntry
+	To   *Item
+	Type string // type of deprecated-by, e.g. NAME_CORRECTION
+}
+
+// Resolution is a current name reached from a name by following deprecations.
+type Resolution struct {
+	Item  *Item
+	Entry *DictionaryEntry  // entry of Item, or nil if Item is not in the dictionary
+	Path  []DeprecationStep // steps from the resolved name to Item, empty if the name is not deprecated
+}
+
+// Resolve follows deprecated-by of the entry whose name is equal to item to the current names, which are not deprecated.
+// A deprecated entry which is deprecated by no names, as by NAME_REMOVAL, and a name which is not in the dictionary
+// are current names too, as they can not be followed anymore.
+// A name reached through several chains is returned once with the first path, in the order of deprecated-by.
+// Returns an error wrapping ErrNotInDictionary if item is not in d, or ErrDeprecationCycle if a chain has a cycle.
+func (d *Dictionary) Resolve(item *Item) ([]Resolution, error) {
+	i, ok := d.names[normalizedItem(item)]
+	if !ok {
+		return nil, fmt.Errorf("cpe:%s: %w", item.Formatted(), ErrNotInDictionary)
+	}
+
+	r := &resolver{
+		d:        d,
+		visited:  map[int]bool{},
+		resolved: map[int][]Resolution{},
+	}
+	return r.resolve(i, nil)
+}
+
+type resolver struct {
+	d        *Dictionary
+	visited  map[int]bool         // entries on the current chain
+	resolved map[int][]Resolution // resolutions of entries which are resolved, whose paths start from the entries
+}
+
+// resolve returns the resolutions of the entry i, whose paths start from it.  path is the chain to i to report a cycle.
+// The resolutions of an entry are cached, so an entry reached through several chains is followed once.
+func (r *resolver) resolve(i int, path []DeprecationStep) ([]Resolution, error) {
+	if resolutions, ok := r.resolved[i]; ok {
+		return resolutions, nil
+	}
+	entry := r.d.entries[i]
+	if !entry.Deprecated || len(entry.DeprecatedBy) == 0 {
+		resolutions := []Resolution{{Item: entry.Item, Entry: entry}}
+		r.resolved[i] = resolutions
+		return resolutions, nil
+	}
+	if r.visited[i] {
+		names := []string{}
+		for _, step := range path {
+			names = append(names, step.From.Item.Formatted())
+		}
+		names = append(names, entry.Item.Formatted())
+		return nil, fmt.Errorf("cpe:%s: %w", strings.Join(names, " -> "), ErrDeprecationCycle)
+	}
+
+	r.visited[i] = true
+	resolutions := []Resolution{}
+	found := map[Item]bool{}
+	add := func(resolution Resolution) {
+		key := normalizedItem(resolution.Item)
+		if !found[key] {
+			found[key] = true
+			resolutions = append(resolutions, resolution)
+		}
+	}
+	for _, by := range entry.DeprecatedBy {
+		step := DeprecationStep{From: entry, To: by.Item, Type: by.Type}
+		j, ok := r.d.names[normalizedItem(by.Item)]
+		if !ok {
+			add(Resolution{Item: by.Item, Path: []DeprecationStep{step}})
+			continue
+		}
+		next, err := r.resolve(j, append(path[:len(path):len(path)], step))
+		if err != nil {
+			return nil, err
+		}
+		for _, resolution := range next {
+			resolution.Path = append([]DeprecationStep{step}, resolution.Path...)
+			add(resolution)
+		}
+	}
+	r.visited[i] = false
+	r.resolved[i] = resolutions
+	return resolutions, nil
+}
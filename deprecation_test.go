@@ -0,0 +1,141 @@
+package cpe
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionaryResolve(t *testing.T) {
+	names := []string{
+		"cpe:2.3:a:old_vendor:product:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:renamed_vendor:product:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:product_server:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:product_client:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:removed:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:cycle_a:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:cycle_b:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:to_cycle:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:diamond:1.0:*:*:*:*:*:*:*",
+	}
+	outside := "cpe:2.3:a:vendor:outside:1.0:*:*:*:*:*:*:*"
+	d := testDictionary(t, names, map[int][]string{
+		0: {names[1]},
+		1: {names[2]},
+		2: {names[3], names[4], outside},
+		5: {},
+		6: {names[7]},
+		7: {names[6]},
+		8: {names[4], names[6]},
+		9: {names[1], names[3]},
+	})
+
+	type resolution struct {
+		Item         string
+		InDictionary bool
+		Path         []string
+	}
+	type testcase struct {
+		input  string
+		expect []resolution
+		err    error
+	}
+	var cases = []testcase{
+		{names[3], []resolution{{names[3], true, []string{}}}, nil},
+		{names[0], []resolution{
+			{names[3], true, []string{names[0], names[1], names[2], names[3]}},
+			{names[4], true, []string{names[0], names[1], names[2], names[4]}},
+			{outside, false, []string{names[0], names[1], names[2], outside}},
+		}, nil},
+		{"cpe:2.3:a:Old_Vendor:Product:1.0:*:*:*:*:*:*:*", []resolution{
+			{names[3], true, []string{names[0], names[1], names[2], names[3]}},
+			{names[4], true, []string{names[0], names[1], names[2], names[4]}},
+			{outside, false, []string{names[0], names[1], names[2], outside}},
+		}, nil},
+		{names[5], []resolution{{names[5], true, []string{}}}, nil},
+		{names[9], []resolution{
+			{names[3], true, []string{names[9], names[1], names[2], names[3]}},
+			{names[4], true, []string{names[9], names[1], names[2], names[4]}},
+			{outside, false, []string{names[9], names[1], names[2], outside}},
+		}, nil},
+		{names[6], nil, ErrDeprecationCycle},
+		{names[8], nil, ErrDeprecationCycle},
+		{outside, nil, ErrNotInDictionary},
+	}
+
+	for i, c := range cases {
+		resolutions, err := d.Resolve(mustParse(t, c.input)[0])
+		assert.True(t, errors.Is(err, c.err), "%d: %v", i, err)
+		var actual []resolution
+		for _, r := range resolutions {
+			path := []string{}
+			for n, step := range r.Path {
+				if n == 0 {
+					path = append(path, step.From.Item.Formatted())
+				}
+				path = append(path, step.To.Formatted())
+				assert.Equal(t, "NAME_CORRECTION", step.Type, "%d", i)
+			}
+			assert.Equal(t, r.Entry != nil && r.Entry.Item == r.Item, r.Entry != nil, "%d", i)
+			actual = append(actual, resolution{r.Item.Formatted(), r.Entry != nil, path})
+		}
+		assert.Equal(t, c.expect, actual, "%d", i)
+	}
+}
+
+func TestDictionaryResolveDiamonds(t *testing.T) {
+	// each layer is a diamond: top_k is deprecated by left_k and right_k, which are deprecated by top_k+1.
+	// the chains double at each layer, so they must not be followed for each of them.
+	const layers = 64
+	names := []string{}
+	deprecatedBy := map[int][]string{}
+	expect := []string{}
+	for k := 0; k <= layers; k++ {
+		top := fmt.Sprintf("cpe:2.3:a:vendor:top_%d:1.0:*:*:*:*:*:*:*", k)
+		expect = append(expect, top)
+		if k == layers {
+			names = append(names, top)
+			break
+		}
+		left := fmt.Sprintf("cpe:2.3:a:vendor:left_%d:1.0:*:*:*:*:*:*:*", k)
+		right := fmt.Sprintf("cpe:2.3:a:vendor:right_%d:1.0:*:*:*:*:*:*:*", k)
+		next := fmt.Sprintf("cpe:2.3:a:vendor:top_%d:1.0:*:*:*:*:*:*:*", k+1)
+		deprecatedBy[len(names)] = []string{left, right}
+		deprecatedBy[len(names)+1] = []string{next}
+		deprecatedBy[len(names)+2] = []string{next}
+		names = append(names, top, left, right)
+		expect = append(expect, left)
+	}
+	d := testDictionary(t, names, deprecatedBy)
+
+	resolutions, err := d.Resolve(mustParse(t, names[0])[0])
+	if assert.Nil(t, err) && assert.Len(t, resolutions, 1) {
+		assert.Equal(t, names[len(names)-1], resolutions[0].Item.Formatted())
+		path := []string{names[0]}
+		for _, step := range resolutions[0].Path {
+			path = append(path, step.To.Formatted())
+		}
+		assert.Equal(t, expect, path)
+	}
+}
+
+func TestDictionarySearchFollowDeprecatedChain(t *testing.T) {
+	names := []string{
+		"cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor:cycle:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor_a:product:1.0:*:*:*:*:*:*:*",
+		"cpe:2.3:a:vendor_b:product:1.0:*:*:*:*:*:*:*",
+	}
+	d := testDictionary(t, names, map[int][]string{
+		0: {names[2]},
+		1: {names[1]},
+		2: {names[3]},
+	})
+	d.Deprecated = FollowDeprecated
+
+	pattern := mustParse(t, "cpe:2.3:a:vendor:*:*:*:*:*:*:*:*:*")[0]
+	assert.Equal(t, []string{names[1], names[3]}, entryNames(d.Search(pattern, SearchNameMatching)))
+}
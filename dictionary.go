@@ -0,0 +1,164 @@
+package cpe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Namespaces of CPE Dictionary documents.
+const (
+	DictionaryNamespace          = "http://cpe.mitre.org/dictionary/2.0"
+	DictionaryExtensionNamespace = "http://scap.nist.gov/schema/cpe-extension/2.3"
+)
+
+// DictionaryEntry reprecents cpe-item of NISTIR 7697, an identifier name in a CPE dictionary.
+type DictionaryEntry struct {
+	Item            *Item
+	Titles          []Title
+	References      []Reference
+	Deprecated      bool
+	DeprecationDate time.Time // zero if it is not known
+	DeprecatedBy    []DeprecatedBy
+}
+
+// Title is a human-readable title of a dictionary entry in the language Lang, e.g. "en-US".
+type Title struct {
+	Lang string
+	Text string
+}
+
+// Reference is a link to a supplementary information of a dictionary entry.
+type Reference struct {
+	Href string
+	Text string
+}
+
+// DeprecatedBy is a name which deprecates a dictionary entry.
+type DeprecatedBy struct {
+	Item *Item
+	Type string // NAME_CORRECTION, NAME_REMOVAL or ADDITIONAL_INFORMATION, or empty if it is not known
+}
+
+type xmlCpeItem struct {
+	Name            string `xml:"name,attr"`
+	Deprecated      bool   `xml:"deprecated,attr"`
+	DeprecatedBy    string `xml:"deprecated_by,attr"`
+	DeprecationDate string `xml:"deprecation_date,attr"`
+	Titles          []struct {
+		Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+		Text string `xml:",chardata"`
+	} `xml:"http://cpe.mitre.org/dictionary/2.0 title"`
+	References []struct {
+		Href string `xml:"href,attr"`
+		Text string `xml:",chardata"`
+	} `xml:"http://cpe.mitre.org/dictionary/2.0 references>reference"`
+	Cpe23Item *struct {
+		Name         string `xml:"name,attr"`
+		Deprecations []struct {
+			Date         string `xml:"date,attr"`
+			DeprecatedBy []struct {
+				Name string `xml:"name,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"http://scap.nist.gov/schema/cpe-extension/2.3 deprecated-by"`
+		} `xml:"http://scap.nist.gov/schema/cpe-extension/2.3 deprecation"`
+	} `xml:"http://scap.nist.gov/schema/cpe-extension/2.3 cpe23-item"`
+}
+
+// DictionaryReader reads entries of a CPE dictionary document one by one, without loading the whole document.
+type DictionaryReader struct {
+	d *xml.Decoder
+}
+
+// NewDictionaryReader returns a DictionaryReader which reads a cpe-list document from r.
+func NewDictionaryReader(r io.Reader) *DictionaryReader {
+	return &DictionaryReader{
+		d: xml.NewDecoder(r),
+	}
+}
+
+// Read returns the next entry.  Returns io.EOF if there are no more entries.
+// The name of the entry is read from cpe23-item as a formatted string, or from cpe-item as a URI if there is no cpe23-item.
+// If a name in an entry is invalid, Read returns an error, and the next call of Read returns the next entry.
+func (r *DictionaryReader) Read() (*DictionaryEntry, error) {
+	for {
+		token, err := r.d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != DictionaryNamespace || start.Name.Local != "cpe-item" {
+			continue
+		}
+
+		var x xmlCpeItem
+		if err := r.d.DecodeElement(&x, &start); err != nil {
+			return nil, err
+		}
+		entry, err := x.entry()
+		if err != nil {
+			line, _ := r.d.InputPos()
+			return nil, fmt.Errorf("cpe:cpe-item %q at line %d: %w", x.Name, line, err)
+		}
+		return entry, nil
+	}
+}
+
+func (x *xmlCpeItem) entry() (*DictionaryEntry, error) {
+	entry := &DictionaryEntry{
+		Deprecated: x.Deprecated,
+	}
+
+	var err error
+	if x.Cpe23Item != nil {
+		entry.Item, err = NewItemFromFormattedString(x.Cpe23Item.Name)
+	} else {
+		entry.Item, err = NewItemFromUri(x.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range x.Titles {
+		entry.Titles = append(entry.Titles, Title{Lang: t.Lang, Text: t.Text})
+	}
+	for _, ref := range x.References {
+		entry.References = append(entry.References, Reference{Href: ref.Href, Text: ref.Text})
+	}
+
+	date := x.DeprecationDate
+	if x.DeprecatedBy != "" {
+		item, err := NewItemFromUri(x.DeprecatedBy)
+		if err != nil {
+			return nil, err
+		}
+		entry.DeprecatedBy = append(entry.DeprecatedBy, DeprecatedBy{Item: item})
+	}
+	if x.Cpe23Item != nil {
+		if len(x.Cpe23Item.Deprecations) > 0 {
+			// names of cpe-23 extension supersede the URI.
+			entry.DeprecatedBy = nil
+			if date == "" {
+				date = x.Cpe23Item.Deprecations[0].Date
+			}
+		}
+		for _, d := range x.Cpe23Item.Deprecations {
+			for _, by := range d.DeprecatedBy {
+				item, err := NewItemFromFormattedString(by.Name)
+				if err != nil {
+					return nil, err
+				}
+				entry.DeprecatedBy = append(entry.DeprecatedBy, DeprecatedBy{Item: item, Type: by.Type})
+			}
+		}
+	}
+
+	if date != "" {
+		if entry.DeprecationDate, err = time.Parse(time.RFC3339Nano, date); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,176 @@
+package cpe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SearchMode is a mode of dictionary search of NISTIR 7697 6.
+type SearchMode int
+
+const (
+	// SearchExact finds entries whose names are equal to the pattern, as CheckEqual.
+	SearchExact = SearchMode(iota)
+	// SearchNameMatching finds entries whose names the pattern matches, as CheckSuperset.
+	SearchNameMatching
+)
+
+func (m SearchMode) String() string {
+	switch m {
+	case SearchExact:
+		return "EXACT"
+	case SearchNameMatching:
+		return "CPE_NAME_MATCHING"
+	}
+	return "unknown search mode"
+}
+
+// DeprecatedPolicy selects how Dictionary.Search handles deprecated entries.
+type DeprecatedPolicy int
+
+const (
+	// IncludeDeprecated returns deprecated entries as other entries.
+	IncludeDeprecated = DeprecatedPolicy(iota)
+	// ExcludeDeprecated does not return deprecated entries.
+	ExcludeDeprecated
+	// FollowDeprecated returns the current entries which a deprecated entry is resolved to by Dictionary.Resolve instead of it.
+	// A deprecated entry is returned if none of the current names are in the dictionary, or the deprecations have a cycle.
+	FollowDeprecated
+)
+
+// Dictionary is a collection of entries of a CPE dictionary, which finds entries by names.
+// A Dictionary is not safe for concurrent use, if it is being modified.
+type Dictionary struct {
+	// Deprecated selects how Search handles deprecated entries.
+	Deprecated DeprecatedPolicy
+
+	entries []*DictionaryEntry
+	names   map[Item]int  // normalized names to positions in entries
+	order   map[*Item]int // names of entries to positions in entries
+	index   *Index
+}
+
+// NewDictionary returns an empty Dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		names: map[Item]int{},
+		order: map[*Item]int{},
+		index: NewIndex(),
+	}
+}
+
+// ReadDictionary reads all entries of a dictionary document from r.
+func ReadDictionary(r io.Reader) (*Dictionary, error) {
+	d := NewDictionary()
+	reader := NewDictionaryReader(r)
+	for {
+		entry, err := reader.Read()
+		if err == io.EOF {
+			return d, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if err := d.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Add adds entry to d.  Returns an error if d has an entry with the same name, as names in a dictionary are unique.
+func (d *Dictionary) Add(entry *DictionaryEntry) error {
+	if entry.Item == nil {
+		return fmt.Errorf("cpe:no name: %w", ErrInvalidDictionaryEntry)
+	}
+	key := normalizedItem(entry.Item)
+	if _, ok := d.names[key]; ok {
+		return fmt.Errorf("cpe:%s is already in the dictionary: %w", entry.Item.Formatted(), ErrInvalidDictionaryEntry)
+	}
+
+	d.names[key] = len(d.entries)
+	d.order[entry.Item] = len(d.entries)
+	d.entries = append(d.entries, entry)
+	d.index.Insert(entry.Item)
+	return nil
+}
+
+// Len returns the number of entries in d.
+func (d *Dictionary) Len() int {
+	return len(d.entries)
+}
+
+// Entries returns all entries of d in the order of addition.
+func (d *Dictionary) Entries() []*DictionaryEntry {
+	return append([]*DictionaryEntry(nil), d.entries...)
+}
+
+// Lookup returns the entry whose name is equal to item.  Returns nil if there is no such entry.
+func (d *Dictionary) Lookup(item *Item) *DictionaryEntry {
+	if i, ok := d.names[normalizedItem(item)]; ok {
+		return d.entries[i]
+	}
+	return nil
+}
+
+// Search returns entries found by pattern in mode, in the order of addition.
+func (d *Dictionary) Search(pattern *Item, mode SearchMode) []*DictionaryEntry {
+	positions := []int{}
+	for _, item := range d.index.Query(pattern) {
+		if mode == SearchExact && !CheckEqual(pattern, item) {
+			continue
+		}
+		positions = append(positions, d.order[item])
+	}
+
+	if d.Deprecated != IncludeDeprecated {
+		found := positions
+		positions = []int{}
+		for _, i := range found {
+			entry := d.entries[i]
+			if !entry.Deprecated {
+				positions = append(positions, i)
+			} else if d.Deprecated == FollowDeprecated {
+				positions = append(positions, d.deprecating(i)...)
+			}
+		}
+	}
+
+	sort.Ints(positions)
+	entries := []*DictionaryEntry{}
+	for n, i := range positions {
+		if n > 0 && positions[n-1] == i {
+			continue
+		}
+		entries = append(entries, d.entries[i])
+	}
+	return entries
+}
+
+// deprecating returns positions of the current entries which the entry at i is resolved to,
+// or i if there are no such entries or the deprecation chains have a cycle.
+func (d *Dictionary) deprecating(i int) []int {
+	resolutions, err := d.Resolve(d.entries[i].Item)
+	if err != nil {
+		return []int{i}
+	}
+	positions := []int{}
+	for _, r := range resolutions {
+		if r.Entry != nil {
+			positions = append(positions, d.order[r.Entry.Item])
+		}
+	}
+	if len(positions) == 0 {
+		return []int{i}
+	}
+	return positions
+}
+
+// normalizedItem returns item with lowercased values, as names are compared case-insensitively.
+func normalizedItem(item *Item) Item {
+	n := *item
+	for _, name := range attrNames[1:] {
+		s := n.stringAttr(name)
+		*s = indexKey(*s)
+	}
+	return n
+}
@@ -0,0 +1,134 @@
+package cpe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDictionary returns a dictionary with entries whose names are names and titles are their indexes.
+// An entry is deprecated by the names in deprecatedBy[index].
+func testDictionary(t *testing.T, names []string, deprecatedBy map[int][]string) *Dictionary {
+	d := NewDictionary()
+	for i, item := range mustParse(t, names...) {
+		entry := &DictionaryEntry{Item: item, Titles: []Title{{"en-US", names[i]}}}
+		if by, ok := deprecatedBy[i]; ok {
+			entry.Deprecated = true
+			entry.DeprecationDate = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			for _, v := range mustParse(t, by...) {
+				entry.DeprecatedBy = append(entry.DeprecatedBy, DeprecatedBy{v, "NAME_CORRECTION"})
+			}
+		}
+		assert.Nil(t, d.Add(entry))
+	}
+	return d
+}
+
+func entryNames(entries []*DictionaryEntry) []string {
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Titles[0].Text)
+	}
+	return names
+}
+
+func TestDictionarySearch(t *testing.T) {
+	names := []string{
+		"cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+		"cpe:2.3:a:microsoft:internet_explorer:8.0.6001:-:*:*:*:*:*:*",
+		"cpe:2.3:a:microsoft:internet_explorer:7.0:-:*:*:*:*:*:*",
+		"cpe:2.3:a:microsoft:Internet_Explorer:8.0:-:*:*:*:*:*:*",
+		"cpe:2.3:a:mozilla:firefox:8.0:-:*:*:*:*:*:*",
+		"cpe:2.3:o:microsoft:windows_xp:-:sp2:*:*:*:*:*:*",
+	}
+	d := testDictionary(t, names, nil)
+	assert.Equal(t, len(names), d.Len())
+
+	type testcase struct {
+		pattern string
+		mode    SearchMode
+		expect  []string
+	}
+	var cases = []testcase{
+		{"cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*", SearchExact, names[0:1]},
+		{"cpe:2.3:a:MICROSOFT:internet_explorer:8.0:-:*:*:*:*:*:*", SearchExact, names[3:4]},
+		{"cpe:2.3:a:microsoft:internet_explorer:8.0.6001:*:*:*:*:*:*:*", SearchExact, []string{}},
+		{"cpe:2.3:a:microsoft:internet_explorer:8.0.6001:*:*:*:*:*:*:*", SearchNameMatching, names[0:2]},
+		{"cpe:2.3:a:microsoft:internet_explorer:8.*:*:*:*:*:*:*:*", SearchNameMatching, []string{names[0], names[1], names[3]}},
+		{"cpe:2.3:a:*:*:8.0:*:*:*:*:*:*:*", SearchNameMatching, []string{names[3], names[4]}},
+		{"cpe:2.3:*:microsoft:*:*:*:*:*:*:*:*:*", SearchNameMatching, []string{names[0], names[1], names[2], names[3], names[5]}},
+		{"cpe:2.3:h:*:*:*:*:*:*:*:*:*:*", SearchNameMatching, []string{}},
+	}
+
+	for i, c := range cases {
+		pattern := mustParse(t, c.pattern)[0]
+		assert.Equal(t, c.expect, entryNames(d.Search(pattern, c.mode)), "%d: %v %s", i, c.mode, c.pattern)
+	}
+
+	assert.Equal(t, names[3], d.Lookup(mustParse(t, "cpe:2.3:a:microsoft:internet_explorer:8.0:-:*:*:*:*:*:*")[0]).Titles[0].Text)
+	assert.Nil(t, d.Lookup(mustParse(t, "cpe:2.3:a:microsoft:internet_explorer:8.0:*:*:*:*:*:*:*")[0]))
+	assert.Equal(t, names, entryNames(d.Entries()))
+}
+
+func TestDictionarySearchDeprecated(t *testing.T) {
+	names := []string{
+		"cpe:2.3:a:1024cms:1024_cms:0.7:*:*:*:*:*:*:*",
+		"cpe:2.3:a:1024cms:1024_cms:1.2.5:*:*:*:*:*:*:*",
+		"cpe:2.3:a:1024cms:1024cms:1.3:*:*:*:*:*:*:*",
+		"cpe:2.3:a:1024cms:1024_cms:1.4:*:*:*:*:*:*:*",
+		"cpe:2.3:a:1024cms:1024_cms:1.5:*:*:*:*:*:*:*",
+	}
+	d := testDictionary(t, names, map[int][]string{
+		0: {names[1]},
+		2: {names[3], names[4]},
+		4: {"cpe:2.3:a:1024cms:1024_cms:2.0:*:*:*:*:*:*:*"},
+	})
+	pattern := mustParse(t, "cpe:2.3:a:1024cms:*:*:*:*:*:*:*:*:*")[0]
+
+	type testcase struct {
+		policy DeprecatedPolicy
+		expect []string
+	}
+	var cases = []testcase{
+		{IncludeDeprecated, names},
+		{ExcludeDeprecated, []string{names[1], names[3]}},
+		// names[4] is deprecated by a name which is not in the dictionary.
+		{FollowDeprecated, []string{names[1], names[3], names[4]}},
+	}
+
+	for i, c := range cases {
+		d.Deprecated = c.policy
+		assert.Equal(t, c.expect, entryNames(d.Search(pattern, SearchNameMatching)), "%d", i)
+	}
+
+	d.Deprecated = FollowDeprecated
+	assert.Equal(t, names[1:2], entryNames(d.Search(mustParse(t, names[0])[0], SearchExact)))
+}
+
+func TestDictionaryAdd(t *testing.T) {
+	d := testDictionary(t, []string{"cpe:2.3:a:microsoft:internet_explorer:8.0:*:*:*:*:*:*:*"}, nil)
+	err := d.Add(&DictionaryEntry{Item: mustParse(t, "cpe:2.3:a:Microsoft:Internet_Explorer:8.0:*:*:*:*:*:*:*")[0]})
+	assert.True(t, errors.Is(err, ErrInvalidDictionaryEntry))
+	err = d.Add(&DictionaryEntry{})
+	assert.True(t, errors.Is(err, ErrInvalidDictionaryEntry))
+	assert.Equal(t, 1, d.Len())
+}
+
+func TestReadDictionary(t *testing.T) {
+	const doc = `<cpe-list xmlns="http://cpe.mitre.org/dictionary/2.0" xmlns:cpe-23="http://scap.nist.gov/schema/cpe-extension/2.3">
+  <cpe-item name="cpe:/a:vendor:product:1.0"><title xml:lang="en-US">1.0</title></cpe-item>
+  <cpe-item name="cpe:/a:vendor:product:2.0"><title xml:lang="en-US">2.0</title></cpe-item>
+</cpe-list>`
+	d, err := ReadDictionary(strings.NewReader(doc))
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"1.0", "2.0"}, entryNames(d.Search(mustParse(t, "cpe:/a:vendor:product")[0], SearchNameMatching)))
+	}
+
+	_, err = ReadDictionary(strings.NewReader(strings.Replace(doc, "product:2.0", "product:1.0", -1)))
+	assert.True(t, errors.Is(err, ErrInvalidDictionaryEntry))
+	_, err = ReadDictionary(strings.NewReader(strings.Replace(doc, "cpe:/a:vendor:product:2.0", "cpe:/a:vendor:product:2.0:1:2:3:4:5", -1)))
+	assert.NotNil(t, err)
+}
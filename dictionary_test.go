@@ -0,0 +1,138 @@
+package cpe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionaryReader(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "dictionary.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := NewDictionaryReader(f)
+
+	entry, err := r.Read()
+	if assert.Nil(t, err) {
+		assert.Equal(t, &DictionaryEntry{
+			Item: mustParse(t, `cpe:2.3:a:\$0.99_kindle_books_project:\$0.99_kindle_books:6:*:*:*:*:android:*:*`)[0],
+			Titles: []Title{
+				{"en-US", "$0.99 Kindle Books project $0.99 Kindle Books (aka com.kindle.books.for99) for android 6.0"},
+				{"ja-JP", "$0.99 Kindle Books プロジェクト $0.99 Kindle Books"},
+			},
+			References: []Reference{
+				{"https://play.google.com/store/apps/details?id=com.kindle.books.for99", "Product information"},
+				{"https://docs.google.com/spreadsheets/d/1t5GXwjw82SyunALVJb2w0zi3FoLRIkfGPc7AMjRF0r4/edit?pli=1#gid=1053404143", "Government Advisory"},
+			},
+		}, entry)
+	}
+
+	entry, err = r.Read()
+	if assert.Nil(t, err) {
+		assert.Equal(t, mustParse(t, "cpe:2.3:a:1024cms:1024_cms:0.7:*:*:*:*:*:*:*")[0], entry.Item)
+		assert.True(t, entry.Deprecated)
+		assert.True(t, time.Date(2011, 1, 12, 19, 35, 43, 650000000, time.UTC).Equal(entry.DeprecationDate))
+		assert.Equal(t, []DeprecatedBy{
+			{mustParse(t, "cpe:2.3:a:1024cms:1024_cms:1.2.5:*:*:*:*:*:*:*")[0], "NAME_CORRECTION"},
+			{mustParse(t, "cpe:2.3:a:1024cms:1024_cms:1.3:*:*:*:*:*:*:*")[0], "NAME_CORRECTION"},
+		}, entry.DeprecatedBy)
+	}
+
+	// an entry without cpe-23 extension.
+	entry, err = r.Read()
+	if assert.Nil(t, err) {
+		assert.Equal(t, mustParse(t, "cpe:/o:microsoft:windows_2000::sp4")[0], entry.Item)
+		assert.True(t, entry.Deprecated)
+		assert.True(t, time.Date(2007, 9, 14, 17, 36, 49, 90000000, time.UTC).Equal(entry.DeprecationDate))
+		assert.Equal(t, []DeprecatedBy{{mustParse(t, "cpe:/o:microsoft:windows_2000:-:sp4")[0], ""}}, entry.DeprecatedBy)
+	}
+
+	// an invalid name does not stop reading.
+	_, err = r.Read()
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr), "%v", err)
+	assert.Contains(t, err.Error(), "cpe:/a:example:broken")
+
+	entry, err = r.Read()
+	if assert.Nil(t, err) {
+		assert.Equal(t, "cpe:/h:cisco:catalyst_2950:-", entry.Item.Uri())
+		assert.False(t, entry.Deprecated)
+		assert.True(t, entry.DeprecationDate.IsZero())
+		assert.Nil(t, entry.DeprecatedBy)
+	}
+
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDictionaryReaderError(t *testing.T) {
+	const header = `<cpe-list xmlns="http://cpe.mitre.org/dictionary/2.0" xmlns:cpe-23="http://scap.nist.gov/schema/cpe-extension/2.3">`
+	type testcase struct {
+		input string
+	}
+	var cases = []testcase{
+		{header + `<cpe-item name="cpe:/a:example:product" deprecation_date="yesterday"/></cpe-list>`},
+		{header + `<cpe-item name="cpe:/a:example:product" deprecated="true" deprecated_by="cpe:/a:example:product:1:2:3:4:5:6"/></cpe-list>`},
+		{header + `<cpe-item name="cpe:/a:example:product"><cpe-23:cpe23-item name="cpe:2.3:a:example:product:*:*:*:*:*:*:*:*"><cpe-23:deprecation><cpe-23:deprecated-by name="cpe:/a:example"/></cpe-23:deprecation></cpe-23:cpe23-item></cpe-item></cpe-list>`},
+		{header + `<cpe-item name="cpe:/a:example:product">`},
+	}
+
+	for i, c := range cases {
+		_, err := NewDictionaryReader(strings.NewReader(c.input)).Read()
+		assert.NotNil(t, err, "%d", i)
+		assert.NotEqual(t, io.EOF, err, "%d", i)
+	}
+}
+
+// dictionaryStream returns a reader of a dictionary with n entries, which is generated while it is read.
+func dictionaryStream(n int) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		fmt.Fprint(pw, `<cpe-list xmlns="http://cpe.mitre.org/dictionary/2.0" xmlns:cpe-23="http://scap.nist.gov/schema/cpe-extension/2.3">`)
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(pw, `<cpe-item name="cpe:/a:vendor%d:product%d:%d"><title xml:lang="en-US">Product %d</title>`+
+				`<cpe-23:cpe23-item name="cpe:2.3:a:vendor%d:product%d:%d:*:*:*:*:*:*:*"/></cpe-item>`, i%100, i, i, i, i%100, i, i)
+		}
+		fmt.Fprint(pw, `</cpe-list>`)
+		pw.Close()
+	}()
+	return pr
+}
+
+func TestDictionaryReaderStream(t *testing.T) {
+	r := NewDictionaryReader(dictionaryStream(10000))
+	n := 0
+	for {
+		entry, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, fmt.Sprintf("Product %d", n), entry.Titles[0].Text)
+		n++
+	}
+	assert.Equal(t, 10000, n)
+}
+
+func BenchmarkDictionaryReader(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		r := NewDictionaryReader(dictionaryStream(1000))
+		for {
+			if _, err := r.Read(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package cpe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// dictionaryDateFormat is the format of dates in the official CPE dictionary.
+const dictionaryDateFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// deprecationTypes are values of type of deprecated-by.
+var deprecationTypes = []string{"NAME_CORRECTION", "NAME_REMOVAL", "ADDITIONAL_INFORMATION"}
+
+// DictionaryWriter writes entries to a CPE dictionary document, a cpe-list with the CPE 2.3 extension.
+type DictionaryWriter struct {
+	e       *xml.Encoder
+	started bool
+	closed  bool
+	err     error
+}
+
+// NewDictionaryWriter returns a DictionaryWriter which writes to w.  Close must be called to finish the document.
+func NewDictionaryWriter(w io.Writer) *DictionaryWriter {
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	return &DictionaryWriter{
+		e: e,
+	}
+}
+
+// Write writes entry as a cpe-item with the name bound to a URI and a formatted string.
+// Returns an error wrapping ErrInvalidDictionaryEntry without writing anything if entry is not valid:
+// names must be identifier names, which have a part, a vendor and a product and have no wildcards
+// and are read back as the same names from both bindings, and entry must have a title.
+// A deprecated entry which has names deprecating it must have the date and types of the deprecation.
+func (w *DictionaryWriter) Write(entry *DictionaryEntry) error {
+	if w.closed {
+		return fmt.Errorf("cpe:write to closed DictionaryWriter")
+	}
+	if err := validateDictionaryEntry(entry); err != nil {
+		return err
+	}
+	if w.err != nil {
+		return w.err
+	}
+
+	w.begin()
+
+	attrs := []xml.Attr{{Name: xml.Name{Local: "name"}, Value: entry.Item.Uri()}}
+	if entry.Deprecated {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "deprecated"}, Value: "true"})
+		if len(entry.DeprecatedBy) > 0 {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "deprecated_by"}, Value: entry.DeprecatedBy[0].Item.Uri()})
+		}
+		if !entry.DeprecationDate.IsZero() {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "deprecation_date"}, Value: entry.DeprecationDate.Format(dictionaryDateFormat)})
+		}
+	}
+	w.start("cpe-item", attrs...)
+
+	for _, t := range entry.Titles {
+		w.start("title", xml.Attr{Name: xml.Name{Local: "xml:lang"}, Value: t.Lang})
+		w.encode(xml.CharData(t.Text))
+		w.end("title")
+	}
+
+	if len(entry.References) > 0 {
+		w.start("references")
+		for _, ref := range entry.References {
+			w.start("reference", xml.Attr{Name: xml.Name{Local: "href"}, Value: ref.Href})
+			w.encode(xml.CharData(ref.Text))
+			w.end("reference")
+		}
+		w.end("references")
+	}
+
+	w.start("cpe-23:cpe23-item", xml.Attr{Name: xml.Name{Local: "name"}, Value: entry.Item.Formatted()})
+	if len(entry.DeprecatedBy) > 0 {
+		w.start("cpe-23:deprecation", xml.Attr{Name: xml.Name{Local: "date"}, Value: entry.DeprecationDate.Format(dictionaryDateFormat)})
+		for _, by := range entry.DeprecatedBy {
+			w.start("cpe-23:deprecated-by",
+				xml.Attr{Name: xml.Name{Local: "name"}, Value: by.Item.Formatted()},
+				xml.Attr{Name: xml.Name{Local: "type"}, Value: by.Type},
+			)
+			w.end("cpe-23:deprecated-by")
+		}
+		w.end("cpe-23:deprecation")
+	}
+	w.end("cpe-23:cpe23-item")
+
+	w.end("cpe-item")
+	return w.err
+}
+
+// Close finishes the document and flushes it.  It does not close the underlying writer.
+func (w *DictionaryWriter) Close() error {
+	if w.err != nil || w.closed {
+		return w.err
+	}
+	w.closed = true
+	w.begin()
+	w.end("cpe-list")
+	if w.err == nil {
+		w.err = w.e.Flush()
+	}
+	return w.err
+}
+
+// begin writes the beginning of the document if it is not written yet.
+func (w *DictionaryWriter) begin() {
+	if w.started {
+		return
+	}
+	w.encode(xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)})
+	w.encode(xml.CharData("\n"))
+	w.start("cpe-list",
+		xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: DictionaryNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:cpe-23"}, Value: DictionaryExtensionNamespace},
+	)
+	w.started = true
+}
+
+// start writes a start element.  Names are written with their prefix, as encoding/xml does not declare prefixes.
+func (w *DictionaryWriter) start(name string, attrs ...xml.Attr) {
+	w.encode(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (w *DictionaryWriter) end(name string) {
+	w.encode(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (w *DictionaryWriter) encode(token xml.Token) {
+	if w.err == nil {
+		w.err = w.e.EncodeToken(token)
+	}
+}
+
+func validateDictionaryEntry(entry *DictionaryEntry) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("cpe:%s: %w", fmt.Sprintf(format, args...), ErrInvalidDictionaryEntry)
+	}
+
+	if entry.Item == nil {
+		return invalid("no name")
+	}
+	if problem := identifierNameProblem(entry.Item); problem != "" {
+		return invalid("%s %s", entry.Item.Formatted(), problem)
+	}
+	name := entry.Item.Formatted()
+
+	if len(entry.Titles) == 0 {
+		return invalid("%s has no title", name)
+	}
+	if len(entry.DeprecatedBy) == 0 {
+		return nil
+	}
+
+	if !entry.Deprecated {
+		return invalid("%s is deprecated by names, but is not deprecated", name)
+	}
+	if entry.DeprecationDate.IsZero() {
+		return invalid("%s has no deprecation date", name)
+	}
+	for _, by := range entry.DeprecatedBy {
+		if by.Item == nil {
+			return invalid("%s is deprecated by no name", name)
+		}
+		if problem := identifierNameProblem(by.Item); problem != "" {
+			return invalid("%s is deprecated by %s, which %s", name, by.Item.Formatted(), problem)
+		}
+		if !isDeprecationType(by.Type) {
+			return invalid("%s has deprecation type %q", name, by.Type)
+		}
+	}
+	return nil
+}
+
+// identifierNameProblem returns a description of the problem if item is not an identifier name of NISTIR 7697.
+// Returns an empty string if item is an identifier name.
+func identifierNameProblem(item *Item) string {
+	if !item.part.IsValid() {
+		return "has no part"
+	}
+	if item.vendor.IsLogical() || item.product.IsLogical() {
+		return "has no vendor or product"
+	}
+	for _, name := range attrNames[1:] {
+		s := item.stringAttr(name)
+		if !s.IsValid() {
+			return "has an invalid " + name
+		}
+		if s.HasWildcard() {
+			return "has wildcards in " + name
+		}
+	}
+
+	// the name is written as a URI and a formatted string, both of which must be read as item.
+	if _, lossy, err := convertToURI(item); err != nil || lossy {
+		return "can not be bound to a URI"
+	}
+	if _, lossy, err := convertToFS(item); err != nil || lossy {
+		return "can not be bound to a formatted string"
+	}
+	return ""
+}
+
+func isDeprecationType(t string) bool {
+	for _, v := range deprecationTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
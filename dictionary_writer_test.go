@@ -0,0 +1,119 @@
+package cpe
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionaryWriter(t *testing.T) {
+	date := time.Date(2011, 1, 12, 14, 35, 43, 650000000, time.FixedZone("", -5*60*60))
+	entries := []*DictionaryEntry{
+		{
+			Item: mustParse(t, `cpe:2.3:a:\$0.99_kindle_books_project:\$0.99_kindle_books:6:*:*:*:*:android:*:*`)[0],
+			Titles: []Title{
+				{"en-US", "$0.99 Kindle Books project $0.99 Kindle Books for android 6.0"},
+				{"ja-JP", "<Kindle> & \"Books\""},
+			},
+			References: []Reference{
+				{"https://example.com/?a=1&b=2", "Product information"},
+			},
+		},
+		{
+			Item:            mustParse(t, "cpe:2.3:a:1024cms:1024_cms:0.7:*:*:*:*:*:*:*")[0],
+			Titles:          []Title{{"en-US", "1024cms.org 1024 CMS 0.7"}},
+			Deprecated:      true,
+			DeprecationDate: date,
+			DeprecatedBy: []DeprecatedBy{
+				{mustParse(t, "cpe:2.3:a:1024cms:1024_cms:1.2.5:*:*:*:*:*:*:*")[0], "NAME_CORRECTION"},
+			},
+		},
+		{
+			Item:       mustParse(t, "cpe:2.3:o:microsoft:windows_2000:-:sp4:*:*:*:*:*:*")[0],
+			Titles:     []Title{{"en-US", "Microsoft Windows 2000 Service Pack 4"}},
+			Deprecated: true,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewDictionaryWriter(buf)
+	for i, entry := range entries {
+		assert.Nil(t, w.Write(entry), "%d", i)
+	}
+	assert.Nil(t, w.Close())
+
+	output := buf.String()
+	assert.Contains(t, output, `<cpe-list xmlns="http://cpe.mitre.org/dictionary/2.0" xmlns:cpe-23="http://scap.nist.gov/schema/cpe-extension/2.3">`)
+	assert.Contains(t, output, `<cpe-item name="cpe:/a:%240.99_kindle_books_project:%240.99_kindle_books:6::~~~android~~">`)
+	assert.Contains(t, output, `<cpe-23:cpe23-item name="cpe:2.3:a:\$0.99_kindle_books_project:\$0.99_kindle_books:6:*:*:*:*:android:*:*"></cpe-23:cpe23-item>`)
+	assert.Contains(t, output, `<cpe-item name="cpe:/a:1024cms:1024_cms:0.7" deprecated="true" deprecated_by="cpe:/a:1024cms:1024_cms:1.2.5" deprecation_date="2011-01-12T14:35:43.650-05:00">`)
+	assert.Contains(t, output, `<cpe-23:deprecation date="2011-01-12T14:35:43.650-05:00">`)
+
+	r := NewDictionaryReader(buf)
+	for i, expect := range entries {
+		entry, err := r.Read()
+		if assert.Nil(t, err, "%d", i) {
+			assert.True(t, expect.DeprecationDate.Equal(entry.DeprecationDate), "%d", i)
+			entry.DeprecationDate = expect.DeprecationDate
+			assert.Equal(t, expect, entry, "%d", i)
+		}
+	}
+	_, err := r.Read()
+	assert.Equal(t, io.EOF, err)
+
+	assert.NotNil(t, w.Write(entries[0]))
+}
+
+func TestDictionaryWriterEmpty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewDictionaryWriter(buf)
+	assert.Nil(t, w.Close())
+
+	_, err := NewDictionaryReader(buf).Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDictionaryWriterValidation(t *testing.T) {
+	item := mustParse(t, "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*")[0]
+	titles := []Title{{"en-US", "Vendor Product 1.0"}}
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var cases = []*DictionaryEntry{
+		{Titles: titles},
+		{Item: item},
+		{Item: mustParse(t, "cpe:2.3:*:vendor:product:1.0:*:*:*:*:*:*:*")[0], Titles: titles},
+		{Item: mustParse(t, "cpe:2.3:a:*:product:1.0:*:*:*:*:*:*:*")[0], Titles: titles},
+		{Item: mustParse(t, "cpe:2.3:a:vendor:-:1.0:*:*:*:*:*:*:*")[0], Titles: titles},
+		{Item: mustParse(t, "cpe:2.3:a:vendor:product:1.*:*:*:*:*:*:*:*")[0], Titles: titles},
+		{Item: mustParse(t, "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:?")[0], Titles: titles},
+		{Item: &Item{part: Application, vendor: NewStringAttr("vendor"), product: NewStringAttr("pro duct")}, Titles: titles},
+		{Item: mustParse(t, `cpe:2.3:a:foo:bar:\-:*:*:*:*:*:*:*`)[0], Titles: titles},
+		{Item: mustParse(t, "cpe:2.3:a:Vendor:product:1.0:*:*:*:*:*:*:*")[0], Titles: titles},
+		{Item: item, Titles: titles, Deprecated: true, DeprecatedBy: []DeprecatedBy{{mustParse(t, `cpe:2.3:a:foo:bar:\-:*:*:*:*:*:*:*`)[0], "NAME_CORRECTION"}}, DeprecationDate: date},
+		{Item: item, Titles: titles, DeprecatedBy: []DeprecatedBy{{item, "NAME_CORRECTION"}}, DeprecationDate: date},
+		{Item: item, Titles: titles, Deprecated: true, DeprecatedBy: []DeprecatedBy{{item, "NAME_CORRECTION"}}},
+		{Item: item, Titles: titles, Deprecated: true, DeprecatedBy: []DeprecatedBy{{item, ""}}, DeprecationDate: date},
+		{Item: item, Titles: titles, Deprecated: true, DeprecatedBy: []DeprecatedBy{{nil, "NAME_CORRECTION"}}, DeprecationDate: date},
+		{Item: item, Titles: titles, Deprecated: true, DeprecatedBy: []DeprecatedBy{{mustParse(t, "cpe:2.3:a:vendor:*:*:*:*:*:*:*:*:*")[0], "NAME_REMOVAL"}}, DeprecationDate: date},
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewDictionaryWriter(buf)
+	for i, c := range cases {
+		err := w.Write(c)
+		assert.True(t, errors.Is(err, ErrInvalidDictionaryEntry), "%d: %v", i, err)
+	}
+	assert.Equal(t, 0, buf.Len())
+
+	// invalid entries do not break the document.
+	assert.Nil(t, w.Write(&DictionaryEntry{Item: item, Titles: titles}))
+	assert.Nil(t, w.Close())
+	entry, err := NewDictionaryReader(buf).Read()
+	if assert.Nil(t, err) {
+		assert.Equal(t, item, entry.Item)
+	}
+}
@@ -1,7 +1,7 @@
 package cpe
 
 import (
-	"strings"
+	"strconv"
 )
 
 type charEncoder []encodeTable
@@ -12,7 +12,7 @@ type encodeTable struct {
 
 var (
 	url_encoder = charEncoder{
-		{"%", "%25"}, // must first
+		{"%", "%25"},
 		{"!", "%21"},
 		{"\"", "%22"},
 		{"#", "%23"},
@@ -41,6 +41,11 @@ var (
 		{"|", "%7c"},
 		{"}", "%7d"},
 		{"~", "%7e"},
+		{"?", "%3f"},
+		{"*", "%2a"},
+	}
+
+	url_special_encoder = charEncoder{
 		{"?", "%01"},
 		{"*", "%02"},
 	}
@@ -75,9 +80,11 @@ var (
 		{"}", "\\}"},
 		{"|", "\\|"},
 		{"~", "\\~"},
+		{"?", "\\?"},
+		{"*", "\\*"},
 	}
 
-	fmt_encoder= charEncoder{
+	fmt_encoder = charEncoder{
 		{"\\", "\\\\"},
 		{"#", "\\#"},
 		{"$", "\\$"},
@@ -105,27 +112,75 @@ var (
 		{"}", "\\}"},
 		{"|", "\\|"},
 		{"~", "\\~"},
+		{"?", "\\?"},
+		{"*", "\\*"},
 	}
 )
 
+// Encode encodes each character in str.  Characters not in t are left as is.
 func (t charEncoder) Encode(str string) string {
-	for _, it := range t {
-		str = it.Encode(str)
+	buf := make([]byte, 0, len(str))
+	for i := 0; i < len(str); i++ {
+		buf = append(buf, t.encode(str[i])...)
 	}
-	return str
+	return string(buf)
 }
 
-func (t charEncoder) Decode(str string) string {
+func (t charEncoder) encode(c byte) string {
 	for _, it := range t {
-		str = it.Decode(str)
+		if it.raw[0] == c {
+			return it.encoded
+		}
 	}
-	return str
+	return string(c)
+}
+
+func isAlnum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func isPunct(c byte) bool {
+	return '!' <= c && c <= '~' && !isAlnum(c)
 }
 
-func (t encodeTable) Encode(str string) string {
-	return strings.Replace(str, t.raw, t.encoded, -1)
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
 }
 
-func (t encodeTable) Decode(str string) string {
-	return strings.Replace(str, t.encoded, t.raw, -1)
+// pctDecode decodes a percent-encoded character at the beginning of str as NISTIR 7695 6.1.3.3.
+// special is true if it is "%01" or "%02", which represent unquoted "?" and "*".
+func pctDecode(str string) (c byte, special, ok bool) {
+	if len(str) < 3 || str[0] != '%' || !isHex(str[1]) || !isHex(str[2]) {
+		return 0, false, false
+	}
+
+	switch str[:3] {
+	case "%01":
+		return '?', true, true
+	case "%02":
+		return '*', true, true
+	}
+
+	n, _ := strconv.ParseUint(str[1:3], 16, 8)
+	c = byte(n)
+	if !isPunct(c) || c == '-' || c == '.' || c == '_' {
+		return 0, false, false
+	}
+	return c, false, true
+}
+
+// splitFmtComponents splits formatted string binding by unquoted ":" from start.  Returns components and its offsets.
+func splitFmtComponents(str string, start int) ([]string, []int) {
+	attrs, offsets := []string{}, []int{start}
+	for i := start; i < len(str); i++ {
+		switch str[i] {
+		case '\\':
+			i++
+		case ':':
+			attrs = append(attrs, str[offsets[len(offsets)-1]:i])
+			offsets = append(offsets, i+1)
+		}
+	}
+	attrs = append(attrs, str[offsets[len(offsets)-1]:])
+	return attrs, offsets
 }
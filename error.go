@@ -0,0 +1,74 @@
+package cpe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Causes of a ParseError.  Use errors.Is to test for them.
+var (
+	ErrInvalidPrefix          = errors.New("invalid prefix")
+	ErrUnexpectedEnd          = errors.New("unexpected end of string")
+	ErrComponentCount         = errors.New("wrong number of components")
+	ErrIllegalCharacter       = errors.New("illegal character")
+	ErrInvalidEscape          = errors.New("invalid escape sequence")
+	ErrInvalidPercentEncoding = errors.New("invalid percent-encoding")
+	ErrInvalidLogicalValue    = errors.New("invalid logical value")
+	ErrUnknownAttribute       = errors.New("unknown attribute")
+	ErrDuplicateAttribute     = errors.New("duplicate attribute")
+	ErrEmptyComponent         = errors.New("empty component")
+)
+
+// ErrInvalidVersion is returned by a VersionComparator if a version is not of the scheme.
+var ErrInvalidVersion = errors.New("invalid version")
+
+// Causes of an error of LogicalTest.Evaluate.
+var (
+	ErrInvalidExpression = errors.New("invalid expression")
+	ErrIndeterminate     = errors.New("relation is undefined")
+	ErrNoChecker         = errors.New("no checker")
+)
+
+// ErrInvalidDictionaryEntry is returned by DictionaryWriter.Write if an entry can not be written to a dictionary.
+var ErrInvalidDictionaryEntry = errors.New("invalid dictionary entry")
+
+// Errors of Dictionary.Resolve.
+var (
+	ErrNotInDictionary  = errors.New("not in the dictionary")
+	ErrDeprecationCycle = errors.New("deprecation cycle")
+)
+
+// ErrInvalidMatchFeed is returned by ReadMatchFeed if the feed is not a NVD CPE match feed.
+var ErrInvalidMatchFeed = errors.New("invalid match feed")
+
+// ErrNoPlatformSpecification is returned by ReadPlatformSpecification if a document has no platform-specification.
+var ErrNoPlatformSpecification = errors.New("no platform-specification")
+
+// ParseError reprecents a failure of NewItemFromWfn, NewItemFromUri or NewItemFromFormattedString.
+type ParseError struct {
+	Binding   Binding // binding which was being parsed
+	Attribute string  // name of attribute being parsed, or empty if the error is not in a attribute
+	Offset    int     // byte offset of the error in the input string
+	Err       error   // cause, one of Err* variables
+}
+
+func (e *ParseError) Error() string {
+	if e.Attribute == "" {
+		return fmt.Sprintf("cpe:invalid %v at offset %d: %v", e.Binding, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("cpe:invalid %v at offset %d (%s): %v", e.Binding, e.Offset, e.Attribute, e.Err)
+}
+
+// Unwrap returns the cause of e.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func newParseError(b Binding, attr string, offset int, err error) *ParseError {
+	return &ParseError{
+		Binding:   b,
+		Attribute: attr,
+		Offset:    offset,
+		Err:       err,
+	}
+}
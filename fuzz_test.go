@@ -0,0 +1,109 @@
+package cpe
+
+import (
+	"testing"
+)
+
+// checkFixedPoint checks that item binds to a string which parses to the same item.
+func checkFixedPoint(t *testing.T, item *Item, bind func(*Item) string, parse func(string) (*Item, error)) {
+	bound := bind(item)
+	again, err := parse(bound)
+	if err != nil {
+		t.Fatalf("%q does not parse: %v", bound, err)
+	}
+	if *again != *item {
+		t.Fatalf("%q parses to %#v, want %#v", bound, *again, *item)
+	}
+	if rebound := bind(again); rebound != bound {
+		t.Fatalf("%q binds to %q", bound, rebound)
+	}
+
+	// other bindings and comparisons must not panic.
+	item.Wfn()
+	item.Uri()
+	item.Formatted()
+	CheckDisjoint(item, again)
+	CheckEqual(item, again)
+	CheckSubset(item, again)
+	CheckSuperset(item, again)
+}
+
+// checkFormattedFixedPoint is checkFixedPoint for the formatted string binding.  A literal "-" binds to "-",
+// which unbinds as NA as NISTIR 7695, so the conversion of such an item is checked to be reported as lossy instead.
+func checkFormattedFixedPoint(t *testing.T, item *Item) {
+	if !hasLiteralHyphen(item) {
+		checkFixedPoint(t, item, (*Item).Formatted, NewItemFromFormattedString)
+		return
+	}
+	if fs, lossy, err := convertToFS(item); err != nil || !lossy {
+		t.Fatalf("%#v converts to %q, lossy %v, %v", *item, fs, lossy, err)
+	}
+}
+
+// hasLiteralHyphen returns true if a value of item is a lone literal "-".
+func hasLiteralHyphen(item *Item) bool {
+	for _, name := range attrNames[1:] {
+		if *item.stringAttr(name) == NewLiteralStringAttr("-") {
+			return true
+		}
+	}
+	return false
+}
+
+func FuzzNewItemFromWfn(f *testing.F) {
+	for _, seed := range []string{
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta",edition=NA]`,
+		`wfn:[part="a",vendor="foo\\bar",product="big\$money_2010",sw_edition="special",target_sw="ipod_touch"]`,
+		`wfn:[vendor="foo\,bar",product="\*?",version="??8\.*"]`,
+		`wfn:[]`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, wfn string) {
+		item, err := NewItemFromWfn(wfn)
+		if err != nil {
+			return
+		}
+		checkFixedPoint(t, item, (*Item).Wfn, NewItemFromWfn)
+		checkFormattedFixedPoint(t, item)
+	})
+}
+
+func FuzzNewItemFromUri(f *testing.F) {
+	for _, seed := range []string{
+		`cpe:/a:microsoft:internet_explorer:8.0.6001:beta`,
+		`cpe:/a:microsoft:internet_explorer:8.%02:sp%01`,
+		`cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~`,
+		`cpe:/a:foo~bar:big%7emoney_2010::::en-us`,
+		`cpe:/`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, uri string) {
+		item, err := NewItemFromUri(uri)
+		if err != nil {
+			return
+		}
+		checkFixedPoint(t, item, (*Item).Uri, NewItemFromUri)
+		checkFixedPoint(t, item, (*Item).Wfn, NewItemFromWfn)
+	})
+}
+
+func FuzzNewItemFromFormattedString(f *testing.F) {
+	for _, seed := range []string{
+		`cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`,
+		`cpe:2.3:a:foo\\bar:big\$money_2010:*:*:*:*:special:ipod_touch:80gb:*`,
+		`cpe:2.3:a:xt-commerce:xt\:commerce:??8.*:-:*:*:*:*:*:*`,
+		`cpe:2.3:*:*:*:*:*:*:*:*:*:*:*`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, fs string) {
+		item, err := NewItemFromFormattedString(fs)
+		if err != nil {
+			return
+		}
+		checkFormattedFixedPoint(t, item)
+		checkFixedPoint(t, item, (*Item).Wfn, NewItemFromWfn)
+	})
+}
@@ -0,0 +1,357 @@
+package cpe
+
+import (
+	"strings"
+)
+
+// Index stores items to find the items which a pattern matches without comparing all of them.
+// Items are grouped by part, vendor and product, and by version in a prefix trie.
+// An Index is not safe for concurrent use.
+type Index struct {
+	parts map[PartAttr]map[StringAttr]map[StringAttr]*indexBucket
+	size  int
+
+	// copied records the maps, buckets and nodes which x has copied since it was derived from another Index.
+	// The others are shared with that Index, so they are copied before x modifies them.
+	// It is nil if x owns all of them.
+	copied map[any]bool
+}
+
+// Keys of Index.copied are indexPartsKey for the map of parts, PartAttr for the map of vendors of a part,
+// indexProductsKey for the map of products of a vendor, and pointers for buckets and nodes.
+type (
+	indexPartsKey    struct{}
+	indexProductsKey struct {
+		part   PartAttr
+		vendor StringAttr
+	}
+)
+
+// indexBucket holds items which have the same part, vendor and product.
+type indexBucket struct {
+	versions versionNode
+	others   map[StringAttr][]*Item // items whose version is a logical value or has wildcards
+}
+
+// versionNode is a node of the prefix trie of lowercased versions.
+type versionNode struct {
+	items    []*Item
+	children map[byte]*versionNode
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		parts: map[PartAttr]map[StringAttr]map[StringAttr]*indexBucket{},
+	}
+}
+
+// Len returns the number of items in the index.
+func (x *Index) Len() int {
+	return x.size
+}
+
+// derive returns an Index with the same items as x, which shares maps, buckets and nodes with x until it modifies them.
+// x must not be modified while the derived Index is used.  A modification copies only the maps, the bucket and the nodes
+// on the path to the item, which are as large as the number of vendors of the part at most, instead of the whole index.
+func (x *Index) derive() *Index {
+	return &Index{
+		parts:  x.parts,
+		size:   x.size,
+		copied: map[any]bool{},
+	}
+}
+
+// owns returns true if x may modify the map, bucket or node identified by key.
+func (x *Index) owns(key any) bool {
+	return x.copied == nil || x.copied[key]
+}
+
+func (x *Index) own(key any) {
+	if x.copied != nil {
+		x.copied[key] = true
+	}
+}
+
+// bucket returns the bucket of the part, vendor and product of item, which x may modify along with the maps to it.
+// Returns nil if there is no such bucket and create is false.
+func (x *Index) bucket(item *Item, create bool) *indexBucket {
+	vendor, product := indexKey(item.vendor), indexKey(item.product)
+	if _, ok := x.parts[item.part][vendor][product]; !ok && !create {
+		return nil
+	}
+
+	if !x.owns(indexPartsKey{}) {
+		x.parts = copyMap(x.parts)
+		x.own(indexPartsKey{})
+	}
+	vendors, ok := x.parts[item.part]
+	if !ok || !x.owns(item.part) {
+		vendors = copyMap(vendors)
+		x.parts[item.part] = vendors
+		x.own(item.part)
+	}
+	productsKey := indexProductsKey{item.part, vendor}
+	products, ok := vendors[vendor]
+	if !ok || !x.owns(productsKey) {
+		products = copyMap(products)
+		vendors[vendor] = products
+		x.own(productsKey)
+	}
+	b, ok := products[product]
+	if !ok {
+		b = &indexBucket{others: map[StringAttr][]*Item{}}
+	} else if !x.owns(b) {
+		b = b.copy()
+	} else {
+		return b
+	}
+	products[product] = b
+	x.own(b)
+	x.own(&b.versions)
+	return b
+}
+
+// copy returns a copy of b which shares the nodes under the root of versions with b.
+func (b *indexBucket) copy() *indexBucket {
+	c := &indexBucket{
+		versions: *b.versions.copy(),
+		others:   make(map[StringAttr][]*Item, len(b.others)),
+	}
+	for k, items := range b.others {
+		c.others[k] = append([]*Item(nil), items...)
+	}
+	return c
+}
+
+// copy returns a copy of n which shares the children with n.
+func (n *versionNode) copy() *versionNode {
+	return &versionNode{
+		items:    append([]*Item(nil), n.items...),
+		children: copyMap(n.children),
+	}
+}
+
+// child returns the child of n at c which x may modify.  It is created if create is true, otherwise nil is returned if it does not exist.
+func (x *Index) child(n *versionNode, c byte, create bool) *versionNode {
+	child, ok := n.children[c]
+	switch {
+	case !ok && !create:
+		return nil
+	case !ok:
+		child = &versionNode{}
+	case !x.owns(child):
+		child = child.copy()
+	default:
+		return child
+	}
+	if n.children == nil {
+		n.children = map[byte]*versionNode{}
+	}
+	n.children[c] = child
+	x.own(child)
+	return child
+}
+
+// copyMap returns a shallow copy of m.  A copy of a nil map is an empty map.
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// Insert adds item to the index.
+func (x *Index) Insert(item *Item) {
+	b := x.bucket(item, true)
+	if key, ok := versionKey(item.version); ok {
+		n := &b.versions
+		for i := 0; i < len(key); i++ {
+			n = x.child(n, key[i], true)
+		}
+		n.items = append(n.items, item)
+	} else {
+		b.others[indexKey(item.version)] = append(b.others[indexKey(item.version)], item)
+	}
+	x.size++
+}
+
+// Remove removes items which are equal to item from the index.  Returns false if there is no such item.
+func (x *Index) Remove(item *Item) bool {
+	b := x.bucket(item, false)
+	if b == nil {
+		return false
+	}
+	vendors := x.parts[item.part]
+	products := vendors[indexKey(item.vendor)]
+
+	removed := 0
+	if key, ok := versionKey(item.version); ok {
+		removed = x.removeVersion(&b.versions, item, key)
+	} else {
+		key := indexKey(item.version)
+		var n int
+		if b.others[key], n = removeItem(b.others[key], item); len(b.others[key]) == 0 {
+			delete(b.others, key)
+		}
+		removed = n
+	}
+	if removed == 0 {
+		return false
+	}
+	x.size -= removed
+
+	if b.versions.isEmpty() && len(b.others) == 0 {
+		delete(products, indexKey(item.product))
+	}
+	if len(products) == 0 {
+		delete(vendors, indexKey(item.vendor))
+	}
+	if len(vendors) == 0 {
+		delete(x.parts, item.part)
+	}
+	return true
+}
+
+// Query returns the items which pattern is a superset of or equal to, as CheckSuperset.  The order of them is unspecified.
+func (x *Index) Query(pattern *Item) []*Item {
+	items := []*Item{}
+	add := func(candidates []*Item) {
+		for _, item := range candidates {
+			if CheckSuperset(pattern, item) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	for part, vendors := range x.parts {
+		if !pattern.part.IsEmpty() && part != pattern.part {
+			continue
+		}
+		lookupIndex(vendors, pattern.vendor, func(products map[StringAttr]*indexBucket) {
+			lookupIndex(products, pattern.product, func(b *indexBucket) {
+				b.query(pattern.version, add)
+			})
+		})
+	}
+	return items
+}
+
+// query calls add with the items in b whose version may match p.
+func (b *indexBucket) query(p StringAttr, add func([]*Item)) {
+	switch {
+	case p == Any:
+		b.versions.walk(add)
+		for _, items := range b.others {
+			add(items)
+		}
+	case p == Na:
+		add(b.others[Na])
+	case !p.HasWildcard():
+		if n := b.versions.find(strings.ToLower(p.literal)); n != nil {
+			add(n.items)
+		}
+	case p.prefix == "":
+		// a value which the pattern matches begins with the literal of the pattern.
+		if n := b.versions.find(strings.ToLower(p.literal)); n != nil {
+			n.walk(add)
+		}
+	default:
+		b.versions.walk(add)
+	}
+}
+
+func (n *versionNode) find(key string) *versionNode {
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.children[key[i]]
+	}
+	return n
+}
+
+func (n *versionNode) walk(add func([]*Item)) {
+	add(n.items)
+	for _, child := range n.children {
+		child.walk(add)
+	}
+}
+
+func (n *versionNode) isEmpty() bool {
+	return len(n.items) == 0 && len(n.children) == 0
+}
+
+// removeVersion removes items equal to item from the node at key under n, and prunes empty nodes.
+// Returns the number of removed items.
+func (x *Index) removeVersion(n *versionNode, item *Item, key string) int {
+	if key == "" {
+		var removed int
+		n.items, removed = removeItem(n.items, item)
+		return removed
+	}
+
+	child := x.child(n, key[0], false)
+	if child == nil {
+		return 0
+	}
+	removed := x.removeVersion(child, item, key[1:])
+	if child.isEmpty() {
+		delete(n.children, key[0])
+	}
+	return removed
+}
+
+// removeItem removes items equal to item from items.  Returns the rest and the number of removed items.
+func removeItem(items []*Item, item *Item) ([]*Item, int) {
+	rest := items[:0]
+	for _, v := range items {
+		if *v != *item {
+			rest = append(rest, v)
+		}
+	}
+	for i := len(rest); i < len(items); i++ {
+		items[i] = nil
+	}
+	return rest, len(items) - len(rest)
+}
+
+// lookupIndex calls f with the values of m whose key may match p.
+func lookupIndex[V any](m map[StringAttr]V, p StringAttr, f func(V)) {
+	if isIndexLiteral(p) {
+		if v, ok := m[indexKey(p)]; ok {
+			f(v)
+		}
+		return
+	}
+	if p == Any {
+		for _, v := range m {
+			f(v)
+		}
+		return
+	}
+	// a value with wildcards matches only values without wildcards.
+	for k, v := range m {
+		if isIndexLiteral(k) && k != Na {
+			f(v)
+		}
+	}
+}
+
+// isIndexLiteral returns true if s matches only values equal to itself, that is NA or a value without wildcards.
+func isIndexLiteral(s StringAttr) bool {
+	return s != Any && !s.HasWildcard()
+}
+
+// versionKey returns the key of s in the prefix trie of versions.
+// Returns false if s is a logical value or has wildcards, which is not stored in the trie.
+func versionKey(s StringAttr) (string, bool) {
+	if s.IsLogical() || s.HasWildcard() {
+		return "", false
+	}
+	return strings.ToLower(s.literal), true
+}
+
+// indexKey normalizes s to a key of Index, as values are compared case-insensitively.
+func indexKey(s StringAttr) StringAttr {
+	s.literal = strings.ToLower(s.literal)
+	return s
+}
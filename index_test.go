@@ -0,0 +1,165 @@
+package cpe
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mustParse returns items parsed from names in any binding, or fails the test.
+func mustParse(t testing.TB, names ...string) []*Item {
+	items := []*Item{}
+	for _, name := range names {
+		item, _, err := Parse(name)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestIndexQuery(t *testing.T) {
+	items := mustParse(t,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.1"]`,
+		`wfn:[part="a",vendor="Microsoft",product="Internet_Explorer",version="7\.0"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version=NA]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*"]`,
+		`wfn:[part="a",vendor="mozilla",product="firefox",version="8\.0"]`,
+		`wfn:[part="o",vendor="microsoft",product="windows_xp",version=NA]`,
+	)
+	x := NewIndex()
+	for _, item := range items {
+		x.Insert(item)
+	}
+	assert.Equal(t, len(items), x.Len())
+
+	type testcase struct {
+		pattern string
+		expect  []*Item
+	}
+	var cases = []testcase{
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*"]`, []*Item{items[0], items[1]}},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="*\.0"]`, []*Item{items[2]}},
+		{`wfn:[part="a",vendor="MICROSOFT",product="internet_explorer",version="7\.0"]`, []*Item{items[2]}},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version=NA]`, []*Item{items[3]}},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer"]`, []*Item{items[0], items[1], items[2], items[3], items[4]}},
+		{`wfn:[vendor="micro*",version=NA]`, []*Item{items[3], items[7]}},
+		{`wfn:[version="?\.0"]`, []*Item{items[2], items[6]}},
+		{`wfn:[part="h"]`, []*Item{}},
+		{`wfn:[]`, []*Item{items[0], items[1], items[2], items[3], items[4], items[6], items[7]}},
+	}
+
+	for i, c := range cases {
+		pattern, err := NewItemFromWfn(c.pattern)
+		assert.Nil(t, err, "%d", i)
+		assert.ElementsMatch(t, c.expect, x.Query(pattern), "%d", i)
+	}
+}
+
+func TestIndexRemove(t *testing.T) {
+	items := mustParse(t,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version=NA]`,
+	)
+	x := NewIndex()
+	for _, item := range items {
+		x.Insert(item)
+	}
+
+	pattern := mustParse(t, `wfn:[part="a"]`)[0]
+	same := mustParse(t, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0"]`)[0]
+	assert.True(t, x.Remove(same))
+	assert.False(t, x.Remove(same))
+	assert.Equal(t, 2, x.Len())
+	assert.ElementsMatch(t, []*Item{items[1], items[2]}, x.Query(pattern))
+
+	assert.True(t, x.Remove(items[2]))
+	assert.True(t, x.Remove(items[1]))
+	assert.Equal(t, 0, x.Len())
+	assert.Equal(t, []*Item{}, x.Query(pattern))
+	assert.Empty(t, x.parts)
+
+	assert.False(t, x.Remove(items[0]))
+}
+
+// randomItem returns a name whose attributes are chosen from a few values,
+// so that names in a small set often match each other.
+func randomItem(r *rand.Rand, wildcards bool) *Item {
+	pick := func(values ...string) StringAttr {
+		v := values[r.Intn(len(values))]
+		switch v {
+		case "ANY":
+			return Any
+		case "NA":
+			return Na
+		}
+		if !wildcards {
+			return NewLiteralStringAttr(v)
+		}
+		return NewStringAttr(v)
+	}
+
+	item := NewItem()
+	item.SetPart([]PartAttr{PartNotSet, Application, OperationgSystem}[r.Intn(3)])
+	item.SetVendor(pick("ANY", "NA", "microsoft", "Microsoft", "mozilla", "micro*"))
+	item.SetProduct(pick("ANY", "windows", "firefox", "?irefox"))
+	item.SetVersion(pick("ANY", "NA", "1", "1.0", "1.0.1", "1.1", "10", "2.0", "1.*", "*.0", "1.?", "?.0"))
+	item.SetUpdate(pick("ANY", "NA", "beta"))
+	return item
+}
+
+func TestIndexQueryRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	x := NewIndex()
+	items := []*Item{}
+	for i := 0; i < 2000; i++ {
+		item := randomItem(r, r.Intn(4) == 0)
+		x.Insert(item)
+		items = append(items, item)
+	}
+	for i := 0; i < 500; i++ {
+		removed := items[r.Intn(len(items))]
+		x.Remove(removed)
+		items, _ = removeItem(items, removed)
+	}
+	assert.Equal(t, len(items), x.Len())
+
+	for i := 0; i < 300; i++ {
+		pattern := randomItem(r, true)
+		expect := []*Item{}
+		for _, m := range (Matcher{}).Match(pattern, itemsOf(items)) {
+			expect = append(expect, m.Item)
+		}
+		assert.ElementsMatch(t, expect, x.Query(pattern), "%d: %s", i, pattern.Wfn())
+	}
+}
+
+func BenchmarkIndexQuery(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		x := NewIndex()
+		for i := 0; i < n; i++ {
+			item := NewItem()
+			item.SetPart(Application)
+			item.SetVendor(NewStringAttr(fmt.Sprintf("vendor%d", i%100)))
+			item.SetProduct(NewStringAttr(fmt.Sprintf("product%d", i%1000)))
+			item.SetVersion(NewStringAttr(fmt.Sprintf("%d.%d", i%7, i)))
+			x.Insert(item)
+		}
+		pattern := NewItem()
+		pattern.SetPart(Application)
+		pattern.SetVendor(NewStringAttr("vendor42"))
+		pattern.SetProduct(NewStringAttr("product42"))
+		pattern.SetVersion(NewStringAttr("0.*"))
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x.Query(pattern)
+			}
+		})
+	}
+}
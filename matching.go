@@ -10,113 +10,140 @@ const (
 	Undefined
 )
 
-// CheckDisjoint implements CPE_DISJOINT.  Returns true if the set-theoretic reration between the names is DISJOINT.
-func CheckDisjoint(src, trg *Item) bool {
-	type obj struct {
-		src Attribute
-		trg Attribute
+func (r Relation) String() string {
+	switch r {
+	case Disjoint:
+		return "DISJOINT"
+	case Equal:
+		return "EQUAL"
+	case Subset:
+		return "SUBSET"
+	case Superset:
+		return "SUPERSET"
 	}
-	for _, v := range []obj{
-		{src.part, trg.part},
-		{src.vendor, trg.vendor},
-		{src.product, trg.product},
-		{src.version, trg.version},
-		{src.update, trg.update},
-		{src.edition, trg.edition},
-		{src.language, trg.language},
-		{src.sw_edition, trg.sw_edition},
-		{src.target_sw, trg.target_sw},
-		{src.target_hw, trg.target_hw},
-		{src.other, trg.other},
-	} {
-		switch v.src.Comparison(v.trg) {
-		case Disjoint:
-			return true
+	return "UNDEFINED"
+}
+
+// CompareResult reprecents relations between two names returned by Compare.
+type CompareResult struct {
+	// Relation is the set-theoretic relation between the names.
+	// It is Undefined if the names are not disjoint, but no other relation is satisfied.
+	Relation Relation
+	// relations between attributes in the order of attrNames.
+	attrs [11]Relation
+}
+
+// Attribute returns the relation between the attributes named name, e.g. "vendor".
+// Returns Undefined if name is not a name of attribute.
+func (r CompareResult) Attribute(name string) Relation {
+	for i, n := range attrNames {
+		if n == name {
+			return r.attrs[i]
 		}
 	}
-	return false
+	return Undefined
 }
 
-// CheckEqual implements CPE_EQUAL.  Returns true if the set-theoretic relation between src and trg is EQUAL.
-func CheckEqual(src, trg *Item) bool {
-	type obj struct {
-		src Attribute
-		trg Attribute
-	}
-	for _, v := range []obj{
-		{src.part, trg.part},
-		{src.vendor, trg.vendor},
-		{src.product, trg.product},
-		{src.version, trg.version},
-		{src.update, trg.update},
-		{src.edition, trg.edition},
-		{src.language, trg.language},
-		{src.sw_edition, trg.sw_edition},
-		{src.target_sw, trg.target_sw},
-		{src.target_hw, trg.target_hw},
-		{src.other, trg.other},
-	} {
-		switch v.src.Comparison(v.trg) {
-		case Equal:
-		default:
-			return false
+// Attributes returns names of attributes which have relation rel, e.g. attributes which make the names DISJOINT.
+func (r CompareResult) Attributes(rel Relation) []string {
+	names := []string{}
+	for i, n := range attrNames {
+		if r.attrs[i] == rel {
+			names = append(names, n)
 		}
 	}
-	return true
+	return names
 }
 
-// CheckSubset implements CPE_SUBSET.  Returns true if the set-theoretic relation between src and trg is SUBSET.
-func CheckSubset(src, trg *Item) bool {
-	type obj struct {
-		src Attribute
-		trg Attribute
+// Matcher compares names with options.  The zero value compares as NISTIR 7696.
+type Matcher struct {
+	// CaseSensitive disables normalizing values to lowercase before comparison.
+	CaseSensitive bool
+	// UndefinedMatches makes Match report targets whose relation to the source is Undefined.
+	// By default they don't match.
+	UndefinedMatches bool
+}
+
+// Match is a target name which matched a source name, and the relation between them.
+type Match struct {
+	Item     *Item
+	Relation Relation
+}
+
+// Match returns targets which src matches in the order of targets, as known instance based matching of NISTIR 7696 7.
+// A target matches if the relation between src and it is SUPERSET or EQUAL.
+// targets is an iterator such as iter.Seq[*Item], e.g. slices.Values(items).
+func (m Matcher) Match(src *Item, targets func(yield func(*Item) bool)) []Match {
+	matches := []Match{}
+	targets(func(trg *Item) bool {
+		switch r := m.Compare(src, trg).Relation; {
+		case r == Superset || r == Equal, r == Undefined && m.UndefinedMatches:
+			matches = append(matches, Match{Item: trg, Relation: r})
+		}
+		return true
+	})
+	return matches
+}
+
+// Compare compares src and trg attribute by attribute as NISTIR 7696 6.
+func Compare(src, trg *Item) CompareResult {
+	return Matcher{}.Compare(src, trg)
+}
+
+// Compare compares src and trg attribute by attribute as NISTIR 7696 6 with the options of m.
+func (m Matcher) Compare(src, trg *Item) CompareResult {
+	result := CompareResult{}
+	result.attrs[0] = src.part.Comparison(trg.part)
+	for i, name := range attrNames[1:] {
+		result.attrs[i+1] = src.stringAttr(name).compare(*trg.stringAttr(name), m.CaseSensitive)
 	}
-	for _, v := range []obj{
-		{src.part, trg.part},
-		{src.vendor, trg.vendor},
-		{src.product, trg.product},
-		{src.version, trg.version},
-		{src.update, trg.update},
-		{src.edition, trg.edition},
-		{src.language, trg.language},
-		{src.sw_edition, trg.sw_edition},
-		{src.target_sw, trg.target_sw},
-		{src.target_hw, trg.target_hw},
-		{src.other, trg.other},
-	} {
-		switch v.src.Comparison(v.trg) {
-		case Subset, Equal:
-		default:
-			return false
+
+	subset, superset := true, true
+	for _, r := range result.attrs {
+		switch r {
+		case Disjoint:
+			result.Relation = Disjoint
+			return result
+		case Subset:
+			superset = false
+		case Superset:
+			subset = false
+		case Undefined:
+			subset, superset = false, false
 		}
 	}
-	return true
+
+	switch {
+	case subset && superset:
+		result.Relation = Equal
+	case subset:
+		result.Relation = Subset
+	case superset:
+		result.Relation = Superset
+	default:
+		result.Relation = Undefined
+	}
+	return result
+}
+
+// CheckDisjoint implements CPE_DISJOINT.  Returns true if the set-theoretic reration between the names is DISJOINT.
+func CheckDisjoint(src, trg *Item) bool {
+	return Compare(src, trg).Relation == Disjoint
+}
+
+// CheckEqual implements CPE_EQUAL.  Returns true if the set-theoretic relation between src and trg is EQUAL.
+func CheckEqual(src, trg *Item) bool {
+	return Compare(src, trg).Relation == Equal
+}
+
+// CheckSubset implements CPE_SUBSET.  Returns true if the set-theoretic relation between src and trg is SUBSET.
+func CheckSubset(src, trg *Item) bool {
+	r := Compare(src, trg).Relation
+	return r == Subset || r == Equal
 }
 
 // CheckSuperset implements CPE_SUPERSET.  Returns true if the set-theoretic relation between src and trg is SUPERSET.
 func CheckSuperset(src, trg *Item) bool {
-	type obj struct {
-		src Attribute
-		trg Attribute
-	}
-	for _, v := range []obj{
-		{src.part, trg.part},
-		{src.vendor, trg.vendor},
-		{src.product, trg.product},
-		{src.version, trg.version},
-		{src.update, trg.update},
-		{src.edition, trg.edition},
-		{src.language, trg.language},
-		{src.sw_edition, trg.sw_edition},
-		{src.target_sw, trg.target_sw},
-		{src.target_hw, trg.target_hw},
-		{src.other, trg.other},
-	} {
-		switch v.src.Comparison(v.trg) {
-		case Superset, Equal:
-		default:
-			return false
-		}
-	}
-	return true
+	r := Compare(src, trg).Relation
+	return r == Superset || r == Equal
 }
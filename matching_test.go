@@ -1,6 +1,7 @@
 package cpe
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -75,7 +76,7 @@ func BenchmarkSuperset(b *testing.B) {
 	item1, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
 	item2, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
 	b.ResetTimer()
-	for i:=0; i< b.N; i++ {
+	for i := 0; i < b.N; i++ {
 		CheckSuperset(item1, item2)
 	}
 }
@@ -84,7 +85,7 @@ func BenchmarkSubset(b *testing.B) {
 	item1, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
 	item2, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
 	b.ResetTimer()
-	for i:=0; i< b.N; i++ {
+	for i := 0; i < b.N; i++ {
 		CheckSubset(item1, item2)
 	}
 }
@@ -93,7 +94,133 @@ func BenchmarkEqual(b *testing.B) {
 	item1, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
 	item2, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
 	b.ResetTimer()
-	for i:=0; i< b.N; i++ {
+	for i := 0; i < b.N; i++ {
 		CheckEqual(item1, item2)
 	}
 }
+
+func TestCompare(t *testing.T) {
+	type testcase struct {
+		src      string
+		trg      string
+		relation Relation
+		disjoint []string
+		subset   []string
+	}
+	var cases = []testcase{
+		{`wfn:[part="o",vendor="microsoft",product="windows_2000"]`, `wfn:[part="o",vendor="microsoft",product="windows_2000"]`, Equal, []string{}, []string{}},
+		{`wfn:[part="o",vendor="microsoft",product="windows_95",update="sp1"]`, `wfn:[part="o",vendor="microsoft",product="windows_2000",update="sp2"]`, Disjoint, []string{"product", "update"}, []string{}},
+		{`wfn:[part="o",vendor="microsoft",product="windows_2000",update="sp3"]`, `wfn:[part="o",vendor="microsoft",product="windows_2000"]`, Subset, []string{}, []string{"update"}},
+		{`wfn:[part="o",vendor="microsoft",product="windows_200*"]`, `wfn:[part="o",vendor="microsoft",product="windows_2000"]`, Superset, []string{}, []string{}},
+		{`wfn:[part="o",vendor="microsoft",update="sp3"]`, `wfn:[part="o",product="windows_2000"]`, Undefined, []string{}, []string{"vendor", "update"}},
+		{`wfn:[part="o",vendor="microsoft",product="windows_2000"]`, `wfn:[part="o",vendor="microsoft",product="windows_200?"]`, Undefined, []string{}, []string{}},
+	}
+
+	for i, c := range cases {
+		src, err := NewItemFromWfn(c.src)
+		assert.Nil(t, err, "%d", i)
+		trg, err := NewItemFromWfn(c.trg)
+		assert.Nil(t, err, "%d", i)
+
+		result := Compare(src, trg)
+		assert.Equal(t, c.relation, result.Relation, "%d", i)
+		assert.Equal(t, c.disjoint, result.Attributes(Disjoint), "%d", i)
+		assert.Equal(t, c.subset, result.Attributes(Subset), "%d", i)
+	}
+
+	src, _ := NewItemFromWfn(`wfn:[part="o",vendor="microsoft",product="windows_200*"]`)
+	trg, _ := NewItemFromWfn(`wfn:[part="o",vendor="microsoft",product="windows_2000",update="sp3"]`)
+	result := Compare(src, trg)
+	assert.Equal(t, Equal, result.Attribute("part"))
+	assert.Equal(t, Superset, result.Attribute("product"))
+	assert.Equal(t, Superset, result.Attribute("update"))
+	assert.Equal(t, Equal, result.Attribute("other"))
+	assert.Equal(t, Undefined, result.Attribute("unknown"))
+	assert.Equal(t, "SUPERSET", result.Relation.String())
+}
+
+func BenchmarkCompare(b *testing.B) {
+	item1, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
+	item2, _ := NewItemFromUri("cpe:/a:microsoft:internet_explorer:8.0.6001:beta")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Compare(item1, item2)
+	}
+}
+
+func TestMatcherCaseSensitive(t *testing.T) {
+	src, _ := NewItemFromWfn(`wfn:[part="a",vendor="Microsoft",product="Internet_Explorer",version="8\.*"]`)
+	trg, _ := NewItemFromWfn(`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001"]`)
+
+	result := Matcher{}.Compare(src, trg)
+	assert.Equal(t, Superset, result.Relation)
+	assert.Equal(t, Equal, result.Attribute("vendor"))
+	assert.Equal(t, result, Compare(src, trg))
+
+	result = Matcher{CaseSensitive: true}.Compare(src, trg)
+	assert.Equal(t, Disjoint, result.Relation)
+	assert.Equal(t, []string{"vendor", "product"}, result.Attributes(Disjoint))
+	assert.Equal(t, Superset, result.Attribute("version"))
+}
+
+func itemsOf(items []*Item) func(yield func(*Item) bool) {
+	return func(yield func(*Item) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	targets := []*Item{}
+	for _, wfn := range []string{
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="7\.0"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0"]`,
+		`wfn:[part="a",vendor="mozilla",product="firefox",version="8\.0"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer"]`,
+	} {
+		item, err := NewItemFromWfn(wfn)
+		assert.Nil(t, err, wfn)
+		targets = append(targets, item)
+	}
+	src, _ := NewItemFromWfn(`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*"]`)
+
+	type testcase struct {
+		matcher Matcher
+		expect  []Match
+	}
+	var cases = []testcase{
+		{Matcher{}, []Match{{targets[0], Superset}, {targets[2], Superset}}},
+		{Matcher{UndefinedMatches: true}, []Match{{targets[0], Superset}, {targets[2], Superset}, {targets[4], Undefined}}},
+	}
+
+	for i, c := range cases {
+		assert.Equal(t, c.expect, c.matcher.Match(src, itemsOf(targets)), "%d", i)
+	}
+
+	assert.Equal(t, []Match{}, Matcher{}.Match(src, itemsOf(nil)))
+}
+
+func BenchmarkMatcherMatch(b *testing.B) {
+	src, _ := NewItemFromWfn(`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*"]`)
+	for _, n := range []int{1000, 10000, 100000} {
+		targets := make([]*Item, n)
+		for i := range targets {
+			item := NewItem()
+			item.SetPart(Application)
+			item.SetVendor(NewStringAttr([]string{"microsoft", "mozilla", "adobe"}[i%3]))
+			item.SetProduct(NewStringAttr("internet_explorer"))
+			item.SetVersion(NewStringAttr(fmt.Sprintf("%d.%d", i%10, i%100)))
+			targets[i] = item
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Matcher{}.Match(src, itemsOf(targets))
+			}
+		})
+	}
+}
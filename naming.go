@@ -37,139 +37,450 @@ func NewItem() *Item {
 	}
 }
 
-var goCpeOriginalDelim = "[goCpeOriginalDelim]"
+// Binding reprecents a string representation of a WFN.
+type Binding int
+
+const (
+	BindingNotSet = Binding(iota)
+	WfnBinding
+	UriBinding
+	FormattedStringBinding
+)
+
+func (b Binding) String() string {
+	switch b {
+	case WfnBinding:
+		return "wfn"
+	case UriBinding:
+		return "uri"
+	case FormattedStringBinding:
+		return "formatted string"
+	}
+	return "unknown binding"
+}
+
+// Parse returns Item parsed from str and the binding of str.  str may be any of WFN, URI and formatted string binding.
+// Whitespaces around str and case of the prefix ("wfn:[", "cpe:/" or "cpe:2.3:") are ignored.
+// Returns *ParseError if str is invalid.
+func Parse(str string) (*Item, Binding, error) {
+	trimmed := strings.TrimSpace(str)
+	lead := strings.Index(str, trimmed)
+
+	var item *Item
+	var err error
+	b := detectBinding(trimmed)
+	switch b {
+	case WfnBinding:
+		item, err = NewItemFromWfn("wfn:[" + trimmed[len("wfn:["):])
+	case UriBinding:
+		item, err = NewItemFromUri("cpe:/" + trimmed[len("cpe:/"):])
+	case FormattedStringBinding:
+		item, err = NewItemFromFormattedString("cpe:2.3:" + trimmed[len("cpe:2.3:"):])
+	default:
+		err = newParseError(BindingNotSet, "", lead, ErrInvalidPrefix)
+	}
+
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok && b != BindingNotSet {
+			perr.Offset += lead
+		}
+		return nil, b, err
+	}
+	return item, b, nil
+}
+
+func detectBinding(str string) Binding {
+	hasPrefix := func(prefix string) bool {
+		return len(str) >= len(prefix) && strings.EqualFold(str[:len(prefix)], prefix)
+	}
+
+	switch {
+	case hasPrefix("wfn:["):
+		return WfnBinding
+	case hasPrefix("cpe:/"):
+		return UriBinding
+	case hasPrefix("cpe:2.3:"):
+		return FormattedStringBinding
+	}
+	return BindingNotSet
+}
+
+// attribute names in the order of formatted string binding.
+var attrNames = []string{
+	"part", "vendor", "product", "version", "update", "edition", "language", "sw_edition", "target_sw", "target_hw", "other",
+}
 
+// NewItemFromWfn returns Item parsed from WFN string.  Returns *ParseError if wfn is invalid.
 func NewItemFromWfn(wfn string) (*Item, error) {
-	if strings.HasPrefix(wfn, "wfn:[") {
-		wfn = strings.TrimPrefix(wfn, "wfn:[")
-	} else {
-		return nil, cpeerr{reason: err_invalid_wfn}
+	if !strings.HasPrefix(wfn, "wfn:[") {
+		return nil, newParseError(WfnBinding, "", 0, ErrInvalidPrefix)
 	}
 
-	if strings.HasSuffix(wfn, "]") {
-		wfn = strings.TrimSuffix(wfn, "]")
+	item := NewItem()
+	seen := map[string]bool{}
+	i := len("wfn:[")
+	if strings.HasPrefix(wfn[i:], "]") {
+		i++
 	} else {
-		return nil, cpeerr{reason: err_invalid_wfn}
+		for {
+			start := i
+			for i < len(wfn) && (wfn[i] == '_' || 'a' <= wfn[i] && wfn[i] <= 'z') {
+				i++
+			}
+			name := wfn[start:i]
+			if i >= len(wfn) {
+				return nil, newParseError(WfnBinding, "", i, ErrUnexpectedEnd)
+			} else if wfn[i] != '=' {
+				return nil, newParseError(WfnBinding, "", i, ErrIllegalCharacter)
+			} else if !isAttrName(name) {
+				return nil, newParseError(WfnBinding, name, start, ErrUnknownAttribute)
+			} else if seen[name] {
+				return nil, newParseError(WfnBinding, name, start, ErrDuplicateAttribute)
+			}
+			seen[name] = true
+			i++
+
+			s, n, err := unbindWfnValue(wfn[i:])
+			if err != nil {
+				return nil, newParseError(WfnBinding, name, i+n, err)
+			}
+			if name == "part" {
+				item.part = newPartAttrFromWfnEncoded(wfn[i : i+n])
+				if !item.part.IsValid() && s != Any {
+					return nil, newParseError(WfnBinding, name, i, ErrIllegalCharacter)
+				}
+			} else {
+				*item.stringAttr(name) = s
+			}
+			i += n
+
+			if i >= len(wfn) {
+				return nil, newParseError(WfnBinding, "", i, ErrUnexpectedEnd)
+			} else if wfn[i] == ']' {
+				i++
+				break
+			} else if wfn[i] != ',' {
+				return nil, newParseError(WfnBinding, name, i, ErrIllegalCharacter)
+			}
+			i++
+		}
 	}
 
-	item := NewItem()
-	for _, attr := range strings.Split(wfn, ",") {
-		sepattr := strings.Split(attr, "=")
-		if len(sepattr) != 2 {
-			return nil, cpeerr{reason: err_invalid_wfn}
+	if i != len(wfn) {
+		return nil, newParseError(WfnBinding, "", i, ErrIllegalCharacter)
+	}
+	return item, nil
+}
+
+// unbindWfnValue reads a attribute value at the beginning of str.
+// Returns the value and length of it, or offset of the invalid character in str.
+func unbindWfnValue(str string) (StringAttr, int, error) {
+	if !strings.HasPrefix(str, "\"") {
+		n := 0
+		for n < len(str) && isAlnum(str[n]) {
+			n++
+		}
+		switch str[:n] {
+		case "ANY":
+			return Any, n, nil
+		case "NA":
+			return Na, n, nil
+		}
+		return Any, 0, ErrInvalidLogicalValue
+	}
+
+	b := stringAttrBuilder{}
+	for i := 1; i < len(str); i++ {
+		c, quoted, at := str[i], false, i
+		switch {
+		case c == '"':
+			if b.attr == Any {
+				return Any, i, ErrIllegalCharacter
+			} else if b.attr.isLoneAsterisk() {
+				return Any, 1, ErrIllegalCharacter
+			}
+			return b.attr, i + 1, nil
+		case c == '\\':
+			if i+1 >= len(str) || !isPunct(str[i+1]) {
+				return Any, at, ErrInvalidEscape
+			}
+			i++
+			c, quoted = str[i], true
+		case isAlnum(c), c == '_', c == '*', c == '?':
+		default:
+			return Any, at, ErrIllegalCharacter
 		}
 
-		n, v := sepattr[0], sepattr[1]
-		switch n {
-		case "part":
-			item.part = newPartAttrFromWfnEncoded(v)
-		case "vendor":
-			item.vendor = newStringAttrFromWfnEncoded(v)
-		case "product":
-			item.product = newStringAttrFromWfnEncoded(v)
-		case "version":
-			item.version = newStringAttrFromWfnEncoded(v)
-		case "update":
-			item.update = newStringAttrFromWfnEncoded(v)
-		case "edition":
-			item.edition = newStringAttrFromWfnEncoded(v)
-		case "language":
-			item.language = newStringAttrFromWfnEncoded(v)
-		case "sw_edition":
-			item.sw_edition = newStringAttrFromWfnEncoded(v)
-		case "target_sw":
-			item.target_sw = newStringAttrFromWfnEncoded(v)
-		case "target_hw":
-			item.target_hw = newStringAttrFromWfnEncoded(v)
-		case "other":
-			item.other = newStringAttrFromWfnEncoded(v)
+		if at, ok := b.add(c, !quoted && (c == '*' || c == '?'), at); !ok {
+			return Any, at, ErrIllegalCharacter
 		}
 	}
+	return Any, len(str), ErrUnexpectedEnd
+}
 
-	return item, nil
+// isAttrName returns true if name is a name of attribute.
+func isAttrName(name string) bool {
+	for _, n := range attrNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
 }
 
+// NewItemFromUri returns Item parsed from URI binding string as unbind_URI of NISTIR 7695 6.1.3.
+// Returns *ParseError if uri is invalid.
 func NewItemFromUri(uri string) (*Item, error) {
-	if strings.HasPrefix(uri, "cpe:/") {
-		uri = strings.TrimPrefix(uri, "cpe:/")
-	} else {
-		return nil, cpeerr{reason: err_invalid_wfn}
+	p := &uriParser{strict: true}
+	return p.parse(uri)
+}
+
+// NewItemFromUriLenient is like NewItemFromUri, but recovers from errors which do not make uri ambiguous.
+// Recovered errors are returned as warnings.
+func NewItemFromUriLenient(uri string) (*Item, []*ParseError, error) {
+	p := &uriParser{strict: false}
+	item, err := p.parse(uri)
+	if err != nil {
+		return nil, p.warnings, err
+	}
+	return item, p.warnings, nil
+}
+
+type uriParser struct {
+	strict   bool
+	warnings []*ParseError
+}
+
+// fail returns *ParseError in strict mode, otherwise records it as a warning and returns nil.
+func (p *uriParser) fail(attr string, offset int, err error) error {
+	perr := newParseError(UriBinding, attr, offset, err)
+	if p.strict {
+		return perr
+	}
+	p.warnings = append(p.warnings, perr)
+	return nil
+}
+
+func (p *uriParser) parse(uri string) (*Item, error) {
+	if !strings.HasPrefix(uri, "cpe:/") {
+		return nil, newParseError(UriBinding, "", 0, ErrInvalidPrefix)
 	}
 
 	item := NewItem()
-	for i, attr := range strings.Split(uri, ":") {
+	offset := len("cpe:/")
+	for i, attr := range strings.Split(uri[offset:], ":") {
+		if i >= 7 {
+			if err := p.fail("", offset-1, ErrComponentCount); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		var err error
 		switch i {
 		case 0:
-			item.part = newPartAttrFromUriEncoded(attr)
-		case 1:
-			item.vendor = newStringAttrFromUriEncoded(attr)
-		case 2:
-			item.product = newStringAttrFromUriEncoded(attr)
-		case 3:
-			item.version = newStringAttrFromUriEncoded(attr)
-		case 4:
-			item.update = newStringAttrFromUriEncoded(attr)
-		case 5:
-			editions := strings.Split(attr, "~")
-			if len(editions) == 1 {
-				item.edition = newStringAttrFromUriEncoded(editions[0])
-			} else if len(editions) == 6 {
-				item.edition = newStringAttrFromUriEncoded(editions[1])
-				item.sw_edition = newStringAttrFromUriEncoded(editions[2])
-				item.target_sw = newStringAttrFromUriEncoded(editions[3])
-				item.target_hw = newStringAttrFromUriEncoded(editions[4])
-				item.other = newStringAttrFromUriEncoded(editions[5])
-			} else {
-				return nil, cpeerr{reason: err_invalid_wfn}
+			switch strings.ToLower(attr) {
+			case "":
+				item.part = PartNotSet
+			case "a", "o", "h":
+				item.part = newPartAttrFromUriEncoded(strings.ToLower(attr))
+			default:
+				return nil, newParseError(UriBinding, attrNames[i], offset, ErrIllegalCharacter)
 			}
+		case 5:
+			err = p.unpackEdition(item, attr, offset)
+		default:
+			*item.stringAttr(attrNames[i]), err = p.decode(attrNames[i], attr, offset)
+		}
+		if err != nil {
+			return nil, err
 		}
+		offset += len(attr) + 1
 	}
 	return item, nil
 }
 
+// unpackEdition sets edition, sw_edition, target_sw, target_hw and other from edition component of URI.
+func (p *uriParser) unpackEdition(item *Item, str string, offset int) error {
+	var err error
+	if !strings.HasPrefix(str, "~") {
+		if i := strings.Index(str, "~"); i >= 0 {
+			// "~" is decoded as a literal in lenient mode.
+			if err := p.fail("edition", offset+i, ErrIllegalCharacter); err != nil {
+				return err
+			}
+		}
+		item.edition, err = p.decode("edition", str, offset)
+		return err
+	}
+
+	packed := strings.Split(str[1:], "~")
+	if len(packed) != 5 {
+		if err := p.fail("edition", offset, ErrComponentCount); err != nil {
+			return err
+		}
+		for len(packed) < 5 {
+			packed = append(packed, "")
+		}
+	}
+
+	offset++
+	for i, name := range []string{"edition", "sw_edition", "target_sw", "target_hw", "other"} {
+		*item.stringAttr(name), err = p.decode(name, packed[i], offset)
+		if err != nil {
+			return err
+		}
+		offset += len(packed[i]) + 1
+	}
+	return nil
+}
+
+// decode implements decode of NISTIR 7695 6.1.3.3.  str is a component of URI and offset is its offset.
+func (p *uriParser) decode(attr, str string, offset int) (StringAttr, error) {
+	if str == "" {
+		return Any, nil
+	} else if str == "-" {
+		return Na, nil
+	}
+
+	str = strings.ToLower(str)
+	b := stringAttrBuilder{}
+	for i := 0; i < len(str); i++ {
+		c, special, at := str[i], false, i
+		switch {
+		case c == '%':
+			d, sp, ok := pctDecode(str[i:])
+			if !ok {
+				// "%" is decoded as a literal in lenient mode.
+				if err := p.fail(attr, offset+at, ErrInvalidPercentEncoding); err != nil {
+					return Any, err
+				}
+				break
+			}
+			c, special = d, sp
+			i += 2
+		case isAlnum(c), c == '-', c == '.', c == '_', c == '~':
+		case isPunct(c):
+			if err := p.fail(attr, offset+at, ErrIllegalCharacter); err != nil {
+				return Any, err
+			}
+		default:
+			return Any, newParseError(UriBinding, attr, offset+at, ErrIllegalCharacter)
+		}
+
+		if at, ok := b.add(c, special, at); !ok {
+			if err := p.fail(attr, offset+at, ErrIllegalCharacter); err != nil {
+				return Any, err
+			}
+		}
+	}
+
+	if b.attr.isLoneAsterisk() {
+		// "%02" is decoded as ANY in lenient mode.
+		if err := p.fail(attr, offset, ErrIllegalCharacter); err != nil {
+			return Any, err
+		}
+		return Any, nil
+	}
+	return b.attr, nil
+}
+
+// NewItemFromFormattedString returns Item parsed from formatted string binding as unbind_fs of NISTIR 7695 6.2.3.
+// Returns *ParseError if str is invalid.
 func NewItemFromFormattedString(str string) (*Item, error) {
-	if strings.HasPrefix(str, "cpe:2.3:") {
-		str = replaceToDelim(strings.TrimPrefix(str, "cpe:2.3:"))
-	} else {
-		return nil, cpeerr{reason: err_invalid_wfn}
+	if !strings.HasPrefix(str, "cpe:2.3:") {
+		return nil, newParseError(FormattedStringBinding, "", 0, ErrInvalidPrefix)
 	}
 
-	attrs := strings.Split(str, ":")
+	attrs, offsets := splitFmtComponents(str, len("cpe:2.3:"))
 	if len(attrs) != 11 {
-		return nil, cpeerr{reason: err_invalid_wfn}
+		offset := len(str)
+		if len(attrs) > 11 {
+			offset = offsets[11] - 1
+		}
+		return nil, newParseError(FormattedStringBinding, "", offset, ErrComponentCount)
 	}
 
 	item := NewItem()
 	for i, attr := range attrs {
-		switch i {
-		case 0:
-			item.part = newPartAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 1:
-			item.vendor = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 2:
-			item.product = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 3:
-			item.version = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 4:
-			item.update = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 5:
-			item.edition = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 6:
-			item.language = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 7:
-			item.sw_edition = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 8:
-			item.target_sw = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 9:
-			item.target_hw = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
-		case 10:
-			item.other = newStringAttrFromFmtEncoded(replaceFromDelim(attr))
+		if i == 0 {
+			item.part = newPartAttrFromFmtEncoded(attr)
+			if !item.part.IsValid() && attr != "*" {
+				return nil, newParseError(FormattedStringBinding, attrNames[i], offsets[i], ErrIllegalCharacter)
+			}
+			continue
 		}
+
+		s, j, err := unbindFmtValue(attr)
+		if err != nil {
+			return nil, newParseError(FormattedStringBinding, attrNames[i], offsets[i]+j, err)
+		}
+		*item.stringAttr(attrNames[i]) = s
 	}
 
 	return item, nil
 }
 
+// unbindFmtValue implements unbind_value_fs of NISTIR 7695 6.2.3.  Returns offset of the invalid character in str.
+func unbindFmtValue(str string) (StringAttr, int, error) {
+	if str == "*" {
+		return Any, 0, nil
+	} else if str == "-" {
+		return Na, 0, nil
+	} else if str == "" {
+		// formatted strings bind ANY as "*", and have no empty components.
+		return Any, 0, ErrEmptyComponent
+	}
+
+	b := stringAttrBuilder{}
+	for i := 0; i < len(str); i++ {
+		c, quoted, at := str[i], false, i
+		switch {
+		case c == '\\':
+			if i+1 >= len(str) || !isPunct(str[i+1]) {
+				return Any, at, ErrInvalidEscape
+			}
+			i++
+			c, quoted = str[i], true
+		case isAlnum(c), c == '-', c == '.', c == '_', c == '*', c == '?':
+		default:
+			return Any, at, ErrIllegalCharacter
+		}
+
+		if at, ok := b.add(c, !quoted && (c == '*' || c == '?'), at); !ok {
+			return Any, at, ErrIllegalCharacter
+		}
+	}
+	return b.attr, 0, nil
+}
+
+// stringAttr returns pointer to the StringAttr of m named name, or nil if there is no such attribute.
+func (m *Item) stringAttr(name string) *StringAttr {
+	switch name {
+	case "vendor":
+		return &m.vendor
+	case "product":
+		return &m.product
+	case "version":
+		return &m.version
+	case "update":
+		return &m.update
+	case "edition":
+		return &m.edition
+	case "language":
+		return &m.language
+	case "sw_edition":
+		return &m.sw_edition
+	case "target_sw":
+		return &m.target_sw
+	case "target_hw":
+		return &m.target_hw
+	case "other":
+		return &m.other
+	}
+	return nil
+}
+
 // Wfn returns a string of Well-Formed string data model.
 func (m *Item) Wfn() string {
 	wfn := "wfn:["
@@ -243,7 +554,7 @@ func (m *Item) Uri() string {
 	}
 
 	uri += ":" + m.language.urlEncoded()
-	return strings.TrimRight(uri, ":*")
+	return strings.TrimRight(uri, ":")
 }
 
 // Wfn returns a formatted string binding.
@@ -428,14 +739,6 @@ func (i *Item) Other() StringAttr {
 	return i.other
 }
 
-func replaceToDelim(str string) string {
-	return strings.Replace(str, "\\:", goCpeOriginalDelim, -1)
-}
-
-func replaceFromDelim(str string) string {
-	return strings.Replace(str, goCpeOriginalDelim, "\\:", -1)
-}
-
 type cpeerr struct {
 	reason string
 	attr   []interface{}
@@ -444,7 +747,6 @@ type cpeerr struct {
 var (
 	err_invalid_type          = "\"%#v\" is not valid as %v attribute."
 	err_invalid_attribute_str = "invalid attribute string."
-	err_invalid_wfn           = "invalid wfn string."
 )
 
 func (e cpeerr) Error() string {
@@ -1,6 +1,7 @@
 package cpe
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -259,7 +260,7 @@ func TestNewItemFromFmt(t *testing.T) {
 		assert.Equal(t, item.TargetHw(), NewStringAttr("80gb"))
 	}
 
-	item, err = NewItemFromFormattedString(`cpe:2.3:a:xt-commerce:xt\\:commerce:*:*:*:*:*:*:*:*`)
+	item, err = NewItemFromFormattedString(`cpe:2.3:a:xt-commerce:xt\:commerce:*:*:*:*:*:*:*:*`)
 	assert.Nil(t, err)
 	if item != nil {
 		assert.Equal(t, item.Part(), Application)
@@ -297,3 +298,289 @@ func BenchmarkNewItemFromFormattedString(b *testing.B) {
 		NewItemFromFormattedString(`cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`)
 	}
 }
+
+func TestParseError(t *testing.T) {
+	type testcase struct {
+		parse  func(string) (*Item, error)
+		input  string
+		expect ParseError
+	}
+	var cases = []testcase{
+		{NewItemFromWfn, `part="a"]`, ParseError{WfnBinding, "", 0, ErrInvalidPrefix}},
+		{NewItemFromWfn, `wfn:[part="a"`, ParseError{WfnBinding, "", 13, ErrUnexpectedEnd}},
+		{NewItemFromWfn, `wfn:[part="a"vendor="microsoft"]`, ParseError{WfnBinding, "part", 13, ErrIllegalCharacter}},
+		{NewItemFromWfn, `wfn:[part="a",vendor="foo-bar"]`, ParseError{WfnBinding, "vendor", 25, ErrIllegalCharacter}},
+		{NewItemFromWfn, `wfn:[part="a",vendor="foo\bar"]`, ParseError{WfnBinding, "vendor", 25, ErrInvalidEscape}},
+		{NewItemFromWfn, `wfn:[part="z"]`, ParseError{WfnBinding, "part", 10, ErrIllegalCharacter}},
+		{NewItemFromUri, `cpe:a:microsoft`, ParseError{UriBinding, "", 0, ErrInvalidPrefix}},
+		{NewItemFromUri, `cpe:/a:microsoft:ie:8:beta::en:foo`, ParseError{UriBinding, "", 30, ErrComponentCount}},
+		{NewItemFromUri, `cpe:/a:micro$oft`, ParseError{UriBinding, "vendor", 12, ErrIllegalCharacter}},
+		{NewItemFromUri, `cpe:/a:micro%2`, ParseError{UriBinding, "vendor", 12, ErrInvalidPercentEncoding}},
+		{NewItemFromUri, `cpe:/a:micro%zzsoft`, ParseError{UriBinding, "vendor", 12, ErrInvalidPercentEncoding}},
+		{NewItemFromUri, `cpe:/a:hp:insight:7.4:-:~~online~win2003~x64`, ParseError{UriBinding, "edition", 24, ErrComponentCount}},
+		{NewItemFromUri, `cpe:/z:microsoft`, ParseError{UriBinding, "part", 5, ErrIllegalCharacter}},
+		{NewItemFromFormattedString, `cpe:/a:microsoft`, ParseError{FormattedStringBinding, "", 0, ErrInvalidPrefix}},
+		{NewItemFromFormattedString, `cpe:2.3:a:microsoft:ie:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "", 36, ErrComponentCount}},
+		{NewItemFromFormattedString, `cpe:2.3:a:microsoft:ie:*:*:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "", 38, ErrComponentCount}},
+		{NewItemFromFormattedString, `cpe:2.3:a:micro$oft:ie:*:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "vendor", 15, ErrIllegalCharacter}},
+		{NewItemFromFormattedString, `cpe:2.3:a:microsoft:i\e:*:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "product", 21, ErrInvalidEscape}},
+		{NewItemFromFormattedString, `cpe:2.3:x:microsoft:ie:*:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "part", 8, ErrIllegalCharacter}},
+		{NewItemFromFormattedString, `cpe:2.3:a::::::::::`, ParseError{FormattedStringBinding, "vendor", 10, ErrEmptyComponent}},
+		{NewItemFromFormattedString, `cpe:2.3:a:microsoft::*:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "product", 20, ErrEmptyComponent}},
+		{NewItemFromFormattedString, `cpe:2.3:a:microsoft:ie:*:*:*:*:*:*:*:`, ParseError{FormattedStringBinding, "other", 37, ErrEmptyComponent}},
+		{NewItemFromFormattedString, `cpe::::::::::::`, ParseError{FormattedStringBinding, "", 0, ErrInvalidPrefix}},
+		{NewItemFromFormattedString, `cpe:2.3::microsoft:ie:*:*:*:*:*:*:*:*`, ParseError{FormattedStringBinding, "part", 8, ErrIllegalCharacter}},
+	}
+
+	for i, c := range cases {
+		_, err := c.parse(c.input)
+		var perr *ParseError
+		if assert.True(t, errors.As(err, &perr), "%d", i) {
+			assert.Equal(t, c.expect, *perr, "%d", i)
+			assert.True(t, errors.Is(err, c.expect.Err), "%d", i)
+		}
+	}
+}
+
+// from 6.1.3.4 Examples of unbinding @ NISTIR-7695-CPE-Naming
+func TestUnbindUri(t *testing.T) {
+	type testcase struct {
+		input  string
+		expect string
+		err    error
+	}
+	var cases = []testcase{
+		{`cpe:/a:microsoft:internet_explorer:8.0.6001:beta`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta"]`, nil},
+		{`cpe:/a:microsoft:internet_explorer:8.%2a:sp%3f`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.\*",update="sp\?"]`, nil},
+		{`cpe:/a:microsoft:internet_explorer:8.%02:sp%01`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*",update="sp?"]`, nil},
+		{`cpe:/a:hp:insight_diagnostics:7.4.0.1570::~~online~win2003~x64~`, `wfn:[part="a",vendor="hp",product="insight_diagnostics",version="7\.4\.0\.1570",sw_edition="online",target_sw="win2003",target_hw="x64"]`, nil},
+		{`cpe:/a:hp:openview_network_manager:7.51:-:~~~linux~~`, `wfn:[part="a",vendor="hp",product="openview_network_manager",version="7\.51",update=NA,target_sw="linux"]`, nil},
+		{`cpe:/a:foo%5cbar:big%24money_2010%07:::~~special~ipod_touch~80gb~`, ``, ErrInvalidPercentEncoding},
+		{`cpe:/a:foo~bar:big%7emoney_2010`, `wfn:[part="a",vendor="foo\~bar",product="big\~money_2010"]`, nil},
+		{`cpe:/a:foo:bar:12.%02.1234`, ``, ErrIllegalCharacter},
+		{`cpe:/a:microsoft:windows:xp:::en-us`, `wfn:[part="a",vendor="microsoft",product="windows",version="xp",language="en\-us"]`, nil},
+		{`cpe:/A:Microsoft:IE:%01%01%2A`, `wfn:[part="a",vendor="microsoft",product="ie",version="??\*"]`, nil},
+		{`cpe:/a:microsoft:ie:%01%021`, ``, ErrIllegalCharacter},
+		{`cpe:/a:microsoft:ie:1%01%02`, ``, ErrIllegalCharacter},
+		{`cpe:/a:microsoft:ie:%2d1`, ``, ErrInvalidPercentEncoding},
+		{`cpe:/a:microsoft:ie:%02`, ``, ErrIllegalCharacter},
+		{`cpe:/a:microsoft:ie:8:sp1:pro~win`, ``, ErrIllegalCharacter},
+	}
+
+	for i, c := range cases {
+		item, err := NewItemFromUri(c.input)
+		if c.err != nil {
+			assert.True(t, errors.Is(err, c.err), "%d: %v", i, err)
+			continue
+		}
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, c.expect, item.Wfn(), "%d", i)
+		}
+	}
+}
+
+func TestNewItemFromUriLenient(t *testing.T) {
+	type testcase struct {
+		input    string
+		expect   string
+		warnings []ParseError
+	}
+	var cases = []testcase{
+		{`cpe:/a:microsoft:internet_explorer:8.0.6001:beta`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta"]`, nil},
+		{`cpe:/a:foo:bar:12.%02.1234`, `wfn:[part="a",vendor="foo",product="bar",version="12\.\*\.1234"]`, []ParseError{
+			{UriBinding, "version", 18, ErrIllegalCharacter},
+		}},
+		{`cpe:/a:foo:bar:1.0%01:beta:pro:en:extra`, `wfn:[part="a",vendor="foo",product="bar",version="1\.0?",update="beta",edition="pro",language="en"]`, []ParseError{
+			{UriBinding, "", 33, ErrComponentCount},
+		}},
+		{`cpe:/a:hp:insight:7.4:-:~~online~win2003~x64`, `wfn:[part="a",vendor="hp",product="insight",version="7\.4",update=NA,sw_edition="online",target_sw="win2003",target_hw="x64"]`, []ParseError{
+			{UriBinding, "edition", 24, ErrComponentCount},
+		}},
+		{`cpe:/a:big%zzmoney$:foo`, `wfn:[part="a",vendor="big\%zzmoney\$",product="foo"]`, []ParseError{
+			{UriBinding, "vendor", 10, ErrInvalidPercentEncoding},
+			{UriBinding, "vendor", 18, ErrIllegalCharacter},
+		}},
+	}
+
+	for i, c := range cases {
+		item, warnings, err := NewItemFromUriLenient(c.input)
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, c.expect, item.Wfn(), "%d", i)
+		}
+		if assert.Equal(t, len(c.warnings), len(warnings), "%d", i) {
+			for j, w := range warnings {
+				assert.Equal(t, c.warnings[j], *w, "%d-%d", i, j)
+			}
+		}
+	}
+
+	_, _, err := NewItemFromUriLenient(`cpe:/z:microsoft`)
+	assert.Error(t, err)
+}
+
+// from 6.2.3.4 Examples of unbinding @ NISTIR-7695-CPE-Naming
+func TestUnbindFormattedString(t *testing.T) {
+	type testcase struct {
+		input  string
+		expect string
+		err    error
+	}
+	var cases = []testcase{
+		{`cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta"]`, nil},
+		{`cpe:2.3:a:microsoft:internet_explorer:8.*:sp?:*:*:*:*:*:*`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*",update="sp?"]`, nil},
+		{`cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:*`, `wfn:[part="a",vendor="hp",product="insight_diagnostics",version="7\.4\.0\.1570",update=NA,sw_edition="online",target_sw="win2003",target_hw="x64"]`, nil},
+		{`cpe:2.3:a:hp:openview_network_manager:7.51:*:*:*:*:linux:*:*`, `wfn:[part="a",vendor="hp",product="openview_network_manager",version="7\.51",target_sw="linux"]`, nil},
+		{`cpe:2.3:a:foo\\bar:big\$money_2010:*:*:*:*:special:ipod_touch:80gb:*`, `wfn:[part="a",vendor="foo\\bar",product="big\$money_2010",sw_edition="special",target_sw="ipod_touch",target_hw="80gb"]`, nil},
+		{`cpe:2.3:a:foo\\:bar:*:*:*:*:*:*:*:*`, `wfn:[part="a",vendor="foo\\",product="bar"]`, nil},
+		{`cpe:2.3:a:xt-commerce:xt\:commerce:*:*:*:*:*:*:*:*`, `wfn:[part="a",vendor="xt\-commerce",product="xt\:commerce"]`, nil},
+		{`cpe:2.3:a:microsoft:internet_explorer:8.\*:sp\?:*:*:*:*:*:*`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.\*",update="sp\?"]`, nil},
+		{`cpe:2.3:a:microsoft:internet_explorer:\*8??:*:*:*:*:*:*:*`, `wfn:[part="a",vendor="microsoft",product="internet_explorer",version="\*8??"]`, nil},
+		{`cpe:2.3:a:hp:insight_diagnostics:7.4.*.1570:*:*:*:*:*:*:*`, ``, ErrIllegalCharacter},
+		{`cpe:2.3:a:hp:insight_diagnostics:7.4?.1570:*:*:*:*:*:*:*`, ``, ErrIllegalCharacter},
+		{`cpe:2.3:a:hp:insight_diagnostics:**7:*:*:*:*:*:*:*`, ``, ErrIllegalCharacter},
+		{`cpe:2.3:a:hp:insight_diagnostics:7\:*:*:*:*:*:*:*`, ``, ErrComponentCount},
+		{`cpe:2.3:a:hp:insight_diagnostics:*:*:*:*:*:*:*:7\`, ``, ErrInvalidEscape},
+	}
+
+	for i, c := range cases {
+		item, err := NewItemFromFormattedString(c.input)
+		if c.err != nil {
+			assert.True(t, errors.Is(err, c.err), "%d: %v", i, err)
+			continue
+		}
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, c.expect, item.Wfn(), "%d", i)
+			assert.Equal(t, c.input, item.Formatted(), "%d", i)
+		}
+	}
+}
+
+func TestNewItemFromWfnTokenizer(t *testing.T) {
+	type testcase struct {
+		input  string
+		expect string
+		err    *ParseError
+	}
+	var cases = []testcase{
+		{`wfn:[]`, `cpe:2.3:*:*:*:*:*:*:*:*:*:*:*`, nil},
+		{`wfn:[vendor="foo\,bar",product="a\=b"]`, `cpe:2.3:*:foo\,bar:a\=b:*:*:*:*:*:*:*:*`, nil},
+		{`wfn:[vendor="say\"hi\"",product="a\]b",version=NA]`, `cpe:2.3:*:say\"hi\":a\]b:-:*:*:*:*:*:*:*`, nil},
+		{`wfn:[part=ANY,vendor="\*soft",product="\*"]`, `cpe:2.3:*:\*soft:\*:*:*:*:*:*:*:*:*`, nil},
+		{`wfn:[vendor="a",vendor="b"]`, ``, &ParseError{WfnBinding, "vendor", 16, ErrDuplicateAttribute}},
+		{`wfn:[vendr="a"]`, ``, &ParseError{WfnBinding, "vendr", 5, ErrUnknownAttribute}},
+		{`wfn:[Vendor="a"]`, ``, &ParseError{WfnBinding, "", 5, ErrIllegalCharacter}},
+		{`wfn:[vendor=any]`, ``, &ParseError{WfnBinding, "vendor", 12, ErrInvalidLogicalValue}},
+		{`wfn:[vendor=NAN]`, ``, &ParseError{WfnBinding, "vendor", 12, ErrInvalidLogicalValue}},
+		{`wfn:[vendor=""]`, ``, &ParseError{WfnBinding, "vendor", 13, ErrIllegalCharacter}},
+		{`wfn:[vendor="*"]`, ``, &ParseError{WfnBinding, "vendor", 13, ErrIllegalCharacter}},
+		{`wfn:[vendor="foo*bar"]`, ``, &ParseError{WfnBinding, "vendor", 16, ErrIllegalCharacter}},
+		{`wfn:[vendor="foo\"]`, ``, &ParseError{WfnBinding, "vendor", 18, ErrIllegalCharacter}},
+		{`wfn:[vendor="foo\"`, ``, &ParseError{WfnBinding, "vendor", 18, ErrUnexpectedEnd}},
+		{`wfn:[vendor="foo"]x`, ``, &ParseError{WfnBinding, "", 18, ErrIllegalCharacter}},
+		{`wfn:[vendor="foo",]`, ``, &ParseError{WfnBinding, "", 18, ErrIllegalCharacter}},
+		{`wfn:[part=NA]`, ``, &ParseError{WfnBinding, "part", 10, ErrIllegalCharacter}},
+		{`wfn:[`, ``, &ParseError{WfnBinding, "", 5, ErrUnexpectedEnd}},
+	}
+
+	for i, c := range cases {
+		item, err := NewItemFromWfn(c.input)
+		if c.err != nil {
+			var perr *ParseError
+			if assert.True(t, errors.As(err, &perr), "%d", i) {
+				assert.Equal(t, *c.err, *perr, "%d", i)
+			}
+			continue
+		}
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, c.expect, item.Formatted(), "%d", i)
+		}
+	}
+}
+
+func TestBindingRoundTrip(t *testing.T) {
+	var cases = []string{
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001",update="beta",edition=NA]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*",update="sp?",edition=NA]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.\*",update="sp\?"]`,
+		`wfn:[part="a",vendor="hp",product="insight_diagnostics",version="7\.4\.0\.1570",sw_edition="online",target_sw="windows_2003",target_hw="x64"]`,
+		`wfn:[part="a",vendor="hp",product="openview_network_manager",version="7\.51",update=NA,target_sw="linux"]`,
+		`wfn:[part="a",vendor="foo\\bar",product="big\$money_2010",sw_edition="special",target_sw="ipod_touch"]`,
+		`wfn:[part="o",vendor="a\\\-b",product="\*\?",version="??1\%25*",language="en\-us",other="x\:y"]`,
+	}
+
+	for i, c := range cases {
+		item, err := NewItemFromWfn(c)
+		if !assert.Nil(t, err, "%d", i) {
+			continue
+		}
+		assert.Equal(t, c, item.Wfn(), "%d", i)
+
+		fromUri, err := NewItemFromUri(item.Uri())
+		if assert.Nil(t, err, "%d: %s", i, item.Uri()) {
+			assert.Equal(t, c, fromUri.Wfn(), "%d", i)
+		}
+
+		fromFmt, err := NewItemFromFormattedString(item.Formatted())
+		if assert.Nil(t, err, "%d: %s", i, item.Formatted()) {
+			assert.Equal(t, c, fromFmt.Wfn(), "%d", i)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	type testcase struct {
+		input   string
+		binding Binding
+		expect  string
+	}
+	var cases = []testcase{
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer"]`, WfnBinding, `cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`},
+		{`  WFN:[part="a",vendor="microsoft",product="internet_explorer"]`, WfnBinding, `cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`},
+		{`cpe:/a:microsoft:internet_explorer`, UriBinding, `cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`},
+		{"\tCPE:/a:microsoft:internet_explorer\n", UriBinding, `cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`},
+		{`cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`, FormattedStringBinding, `cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`},
+		{` Cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:* `, FormattedStringBinding, `cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*`},
+	}
+
+	for i, c := range cases {
+		item, b, err := Parse(c.input)
+		if assert.Nil(t, err, "%d", i) {
+			assert.Equal(t, c.binding, b, "%d", i)
+			assert.Equal(t, c.expect, item.Formatted(), "%d", i)
+		}
+	}
+
+	_, b, err := Parse(`  cpe:2.3:a:micro$oft:*:*:*:*:*:*:*:*:*`)
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, FormattedStringBinding, b)
+		assert.Equal(t, ParseError{FormattedStringBinding, "vendor", 17, ErrIllegalCharacter}, *perr)
+	}
+
+	_, b, err = Parse(` a:microsoft`)
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, BindingNotSet, b)
+		assert.Equal(t, ParseError{BindingNotSet, "", 1, ErrInvalidPrefix}, *perr)
+	}
+}
+
+func TestZeroValueItem(t *testing.T) {
+	item := &Item{}
+	assert.Equal(t, `wfn:[]`, item.Wfn())
+	assert.Equal(t, `cpe:/`, item.Uri())
+	assert.Equal(t, `cpe:2.3:*:*:*:*:*:*:*:*:*:*:*`, item.Formatted())
+	assert.Equal(t, PartNotSet, item.Part())
+	assert.Equal(t, Any, item.Vendor())
+	assert.Equal(t, *NewItem(), *item)
+	assert.True(t, CheckEqual(item, NewItem()))
+	assert.True(t, CheckSuperset(item, &Item{part: Application, vendor: NewStringAttr("microsoft")}))
+
+	item.part = PartAttr('z')
+	assert.Equal(t, `wfn:[part=ANY]`, item.Wfn())
+	assert.Equal(t, `cpe:/`, item.Uri())
+	assert.Equal(t, `cpe:2.3:*:*:*:*:*:*:*:*:*:*:*`, item.Formatted())
+	assert.False(t, CheckEqual(item, item))
+	assert.Error(t, item.SetPart(PartAttr('z')))
+	assert.Nil(t, item.SetPart(Hardware))
+	assert.Equal(t, `cpe:/h`, item.Uri())
+}
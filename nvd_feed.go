@@ -0,0 +1,188 @@
+package cpe
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MatchCriteria is a criteria of NVD CPE match feed, a name which may have wildcards and bounds of its version.
+type MatchCriteria struct {
+	Item  *Item
+	Range VersionRange // Comparator is not used by MatchFeed
+}
+
+// MatchFeedEntry is an entry of NVD CPE match feed, a criteria and the names in NVD which it matches.
+type MatchFeedEntry struct {
+	Criteria MatchCriteria
+	Names    []*Item
+}
+
+// MatchFeed reprecents NVD CPE match feed (nvdcpematch-1.0.json.gz), which expands criterias to names.
+type MatchFeed struct {
+	entries  []*MatchFeedEntry
+	criteria map[matchFeedKey]int // positions in entries
+}
+
+type matchFeedKey struct {
+	item                                                       Item
+	startIncluding, startExcluding, endIncluding, endExcluding string
+}
+
+type jsonMatch struct {
+	Cpe23Uri              string `json:"cpe23Uri"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+	Names                 []struct {
+		Cpe23Uri string `json:"cpe23Uri"`
+	} `json:"cpe_name"`
+}
+
+// LoadMatchFeed reads a gzipped NVD CPE match feed from the file at path, as ReadMatchFeed.
+func LoadMatchFeed(path string) (*MatchFeed, []error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return ReadMatchFeed(f)
+}
+
+// ReadMatchFeed reads a gzipped NVD CPE match feed from r.
+// Matches are decoded one by one, so the whole feed is not loaded in memory at once.
+// Entries with the same criteria are merged.
+// Names which are invalid are skipped, and returned as invalid, errors wrapping *ParseError, as DictionaryReader does.
+// A match whose criteria is invalid is skipped with its names.  err is not nil only if the feed itself is broken.
+func ReadMatchFeed(r io.Reader) (feed *MatchFeed, invalid []error, err error) {
+	z, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer z.Close()
+
+	feed = &MatchFeed{
+		criteria: map[matchFeedKey]int{},
+	}
+	d := json.NewDecoder(z)
+	if err := expectDelim(d, '{'); err != nil {
+		return nil, nil, err
+	}
+	for d.More() {
+		token, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		if token != "matches" {
+			// skip values of other keys.
+			var v json.RawMessage
+			if err := d.Decode(&v); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if err := expectDelim(d, '['); err != nil {
+			return nil, nil, err
+		}
+		for d.More() {
+			var m jsonMatch
+			if err := d.Decode(&m); err != nil {
+				return nil, nil, err
+			}
+			invalid = append(invalid, feed.add(&m)...)
+		}
+		if err := expectDelim(d, ']'); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := expectDelim(d, '}'); err != nil {
+		return nil, nil, err
+	}
+	return feed, invalid, nil
+}
+
+func expectDelim(d *json.Decoder, delim json.Delim) error {
+	token, err := d.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("cpe:%v is not %v: %w", token, delim, ErrInvalidMatchFeed)
+	}
+	return nil
+}
+
+// add adds the entry of m to f.  Returns errors of invalid names, which are skipped.
+func (f *MatchFeed) add(m *jsonMatch) []error {
+	item, err := NewItemFromFormattedString(m.Cpe23Uri)
+	if err != nil {
+		return []error{fmt.Errorf("cpe:cpe23Uri %q: %w", m.Cpe23Uri, err)}
+	}
+	criteria := MatchCriteria{
+		Item: item,
+		Range: VersionRange{
+			StartIncluding: m.VersionStartIncluding,
+			StartExcluding: m.VersionStartExcluding,
+			EndIncluding:   m.VersionEndIncluding,
+			EndExcluding:   m.VersionEndExcluding,
+		},
+	}
+
+	var invalid []error
+	names := make([]*Item, 0, len(m.Names))
+	for _, name := range m.Names {
+		item, err := NewItemFromFormattedString(name.Cpe23Uri)
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("cpe:cpe_name %q of %q: %w", name.Cpe23Uri, m.Cpe23Uri, err))
+			continue
+		}
+		names = append(names, item)
+	}
+
+	key := criteria.key()
+	if i, ok := f.criteria[key]; ok {
+		f.entries[i].Names = append(f.entries[i].Names, names...)
+		return invalid
+	}
+	f.criteria[key] = len(f.entries)
+	f.entries = append(f.entries, &MatchFeedEntry{Criteria: criteria, Names: names})
+	return invalid
+}
+
+// key returns the key of c in MatchFeed, whose name is compared case-insensitively as CPE name matching.
+func (c *MatchCriteria) key() matchFeedKey {
+	return matchFeedKey{
+		item:           normalizedItem(c.Item),
+		startIncluding: c.Range.StartIncluding,
+		startExcluding: c.Range.StartExcluding,
+		endIncluding:   c.Range.EndIncluding,
+		endExcluding:   c.Range.EndExcluding,
+	}
+}
+
+// Len returns the number of criterias in f.
+func (f *MatchFeed) Len() int {
+	return len(f.entries)
+}
+
+// Entries returns all entries of f in the order of the feed.
+func (f *MatchFeed) Entries() []*MatchFeedEntry {
+	return append([]*MatchFeedEntry(nil), f.entries...)
+}
+
+// Lookup returns the names which criteria matches.  The second value is false if criteria is not in f.
+// Item of criteria is compared by value case-insensitively, and Comparator of Range is ignored.
+func (f *MatchFeed) Lookup(criteria MatchCriteria) ([]*Item, bool) {
+	if criteria.Item == nil {
+		return nil, false
+	}
+	i, ok := f.criteria[criteria.key()]
+	if !ok {
+		return nil, false
+	}
+	return append([]*Item(nil), f.entries[i].Names...), true
+}
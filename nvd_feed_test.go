@@ -0,0 +1,141 @@
+package cpe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMatchFeed(t *testing.T) {
+	feed, invalid, err := LoadMatchFeed("testdata/nvdcpematch.json.gz")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Empty(t, invalid)
+	assert.Equal(t, 6, feed.Len())
+
+	type testcase struct {
+		criteria string
+		r        VersionRange
+		expect   []string
+		found    bool
+	}
+	var cases = []testcase{
+		{"cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*", VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.3"}, []string{
+			"cpe:2.3:a:apache:http_server:2.4.0:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:http_server:2.4.1:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:http_server:2.4.2:*:*:*:*:*:*:*",
+		}, true},
+		{"cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*", VersionRange{EndIncluding: "2.4.0"}, []string{
+			"cpe:2.3:a:apache:http_server:2.2.34:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:http_server:2.4.0:*:*:*:*:*:*:*",
+		}, true},
+		{"cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*", VersionRange{}, []string{
+			"cpe:2.3:a:apache:http_server:2.2.34:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:http_server:2.4.0:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:http_server:2.4.1:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:http_server:2.4.2:*:*:*:*:*:*:*",
+		}, true},
+		{"cpe:2.3:a:openssl:openssl:1.0.1:*:*:*:*:*:*:*", VersionRange{}, []string{
+			"cpe:2.3:a:openssl:openssl:1.0.1:-:*:*:*:*:*:*",
+			"cpe:2.3:a:openssl:openssl:1.0.1:beta1:*:*:*:*:*:*",
+		}, true},
+		{"cpe:2.3:a:vendor:withdrawn:*:*:*:*:*:*:*:*", VersionRange{EndExcluding: "1.0"}, []string{}, true},
+		{"cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*", VersionRange{EndExcluding: "2.4.0"}, []string{}, false},
+		{"cpe:2.3:a:openssl:openssl:1.0.2:*:*:*:*:*:*:*", VersionRange{}, []string{}, false},
+	}
+
+	for i, c := range cases {
+		criteria := MatchCriteria{Item: mustParse(t, c.criteria)[0], Range: c.r}
+		names, found := feed.Lookup(criteria)
+		assert.Equal(t, c.found, found, "%d", i)
+		actual := []string{}
+		for _, name := range names {
+			actual = append(actual, name.Formatted())
+		}
+		assert.Equal(t, c.expect, actual, "%d", i)
+	}
+
+	// names of the feed are the names which criterias match by CPE name matching and version ranges.
+	for i, entry := range feed.Entries() {
+		for _, name := range entry.Names {
+			assert.True(t, CheckSuperset(entry.Criteria.Item, name), "%d: %s", i, name.Formatted())
+			ok, err := entry.Criteria.Range.Match(name)
+			assert.Nil(t, err, "%d", i)
+			assert.True(t, ok, "%d: %s", i, name.Formatted())
+		}
+	}
+}
+
+func gzipped(s string) *bytes.Buffer {
+	b := &bytes.Buffer{}
+	w := gzip.NewWriter(b)
+	w.Write([]byte(s))
+	w.Close()
+	return b
+}
+
+func TestReadMatchFeed(t *testing.T) {
+	feed, invalid, err := ReadMatchFeed(gzipped(`{"version": "1.0", "matches": [
+		{"cpe23Uri": "cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*", "cpe_name": [{"cpe23Uri": "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*"}]},
+		{"cpe23Uri": "cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*", "cpe_name": [{"cpe23Uri": "cpe:2.3:a:vendor:product:2.0:*:*:*:*:*:*:*"}]}
+	]}`))
+	if assert.Nil(t, err) {
+		assert.Empty(t, invalid)
+		assert.Equal(t, 1, feed.Len())
+		names, found := feed.Lookup(MatchCriteria{Item: mustParse(t, "cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*")[0]})
+		assert.True(t, found)
+		assert.Equal(t, mustParse(t, "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:2.0:*:*:*:*:*:*:*"), names)
+	}
+
+	// criterias are merged and looked up case-insensitively.
+	feed, invalid, err = ReadMatchFeed(gzipped(`{"matches": [
+		{"cpe23Uri": "cpe:2.3:a:Vendor:Product:*:*:*:*:*:*:*:*", "cpe_name": [{"cpe23Uri": "cpe:2.3:a:Vendor:Product:1.0:*:*:*:*:*:*:*"}]},
+		{"cpe23Uri": "cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*", "cpe_name": [{"cpe23Uri": "cpe:2.3:a:vendor:product:2.0:*:*:*:*:*:*:*"}]}
+	]}`))
+	if assert.Nil(t, err) {
+		assert.Empty(t, invalid)
+		assert.Equal(t, 1, feed.Len())
+		for _, criteria := range []string{"cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*", "cpe:2.3:a:VENDOR:Product:*:*:*:*:*:*:*:*"} {
+			names, found := feed.Lookup(MatchCriteria{Item: mustParse(t, criteria)[0]})
+			assert.True(t, found, criteria)
+			assert.Equal(t, mustParse(t, "cpe:2.3:a:Vendor:Product:1.0:*:*:*:*:*:*:*", "cpe:2.3:a:vendor:product:2.0:*:*:*:*:*:*:*"), names, criteria)
+		}
+	}
+
+	// invalid names are skipped, and the others are loaded.
+	feed, invalid, err = ReadMatchFeed(gzipped(`{"matches": [
+		{"cpe23Uri": "cpe:/a:vendor:product", "cpe_name": [{"cpe23Uri": "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*"}]},
+		{"cpe23Uri": "cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*", "cpe_name": [
+			{"cpe23Uri": "cpe:2.3:a"},
+			{"cpe23Uri": "cpe:2.3:a:vendor:product:2.0:*:*:*:*:*:*:*"},
+			{"cpe23Uri": "cpe:2.3:a:vendor:product::*:*:*:*:*:*:*"}
+		]}
+	]}`))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, feed.Len())
+		names, _ := feed.Lookup(MatchCriteria{Item: mustParse(t, "cpe:2.3:a:vendor:product:*:*:*:*:*:*:*:*")[0]})
+		assert.Equal(t, mustParse(t, "cpe:2.3:a:vendor:product:2.0:*:*:*:*:*:*:*"), names)
+		if assert.Equal(t, 3, len(invalid)) {
+			for i, err := range invalid {
+				var perr *ParseError
+				assert.True(t, errors.As(err, &perr), "%d: %v", i, err)
+			}
+			assert.True(t, errors.Is(invalid[0], ErrInvalidPrefix))
+			assert.True(t, errors.Is(invalid[1], ErrComponentCount))
+			assert.True(t, errors.Is(invalid[2], ErrEmptyComponent))
+		}
+	}
+
+	_, _, err = ReadMatchFeed(gzipped(`[]`))
+	assert.True(t, errors.Is(err, ErrInvalidMatchFeed))
+	_, _, err = ReadMatchFeed(gzipped(`{"matches": [{"cpe23Uri": 1}]}`))
+	assert.NotNil(t, err)
+	_, _, err = ReadMatchFeed(bytes.NewBufferString(`{"matches": []}`))
+	assert.NotNil(t, err)
+	_, _, err = LoadMatchFeed("testdata/no-such-feed.json.gz")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,97 @@
+package cpe
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Store is a collection of items which is safe for concurrent use.
+// Queries read an immutable snapshot, and updates replace it with a modified copy,
+// so that an update never blocks queries and a query never sees a partial update.
+// Items in a Store must not be modified.
+type Store struct {
+	mu       sync.Mutex // serializes updates
+	snapshot atomic.Pointer[Snapshot]
+}
+
+// Snapshot is an immutable state of Store.
+type Snapshot struct {
+	index *Index
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	s := &Store{}
+	s.snapshot.Store(&Snapshot{index: NewIndex()})
+	return s
+}
+
+// Snapshot returns the current state of s.  It is not affected by later updates.
+func (s *Store) Snapshot() *Snapshot {
+	return s.snapshot.Load()
+}
+
+// Query returns the items in the current state of s which pattern is a superset of or equal to.
+func (s *Store) Query(pattern *Item) []*Item {
+	return s.Snapshot().Query(pattern)
+}
+
+// Len returns the number of items in the current state of s.
+func (s *Store) Len() int {
+	return s.Snapshot().Len()
+}
+
+// Insert adds items to s at once.
+func (s *Store) Insert(items ...*Item) {
+	s.Update(func(x *Index) {
+		for _, item := range items {
+			x.Insert(item)
+		}
+	})
+}
+
+// Remove removes items which are equal to one of items from s at once.
+func (s *Store) Remove(items ...*Item) {
+	s.Update(func(x *Index) {
+		for _, item := range items {
+			x.Remove(item)
+		}
+	})
+}
+
+// Replace replaces all items in s with items, e.g. to reload a dictionary.
+func (s *Store) Replace(items []*Item) {
+	x := NewIndex()
+	for _, item := range items {
+		x.Insert(item)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot.Store(&Snapshot{index: x})
+}
+
+// Update calls f with a copy of the current index, and makes it the current state of s after f returns.
+// f must not keep x.  Updates are serialized, but queries are not blocked while f is running.
+// The copy shares the index with the current state, and copies only what f modifies, so that the cost of
+// an update is proportional to the items it inserts or removes and the number of vendors of their parts.
+// Use Replace to replace most of the items.
+func (s *Store) Update(f func(x *Index)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x := s.snapshot.Load().index.derive()
+	f(x)
+	x.copied = nil
+	s.snapshot.Store(&Snapshot{index: x})
+}
+
+// Query returns the items which pattern is a superset of or equal to, as CheckSuperset.  The order of them is unspecified.
+func (s *Snapshot) Query(pattern *Item) []*Item {
+	return s.index.Query(pattern)
+}
+
+// Len returns the number of items in s.
+func (s *Snapshot) Len() int {
+	return s.index.Len()
+}
@@ -0,0 +1,207 @@
+package cpe
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	items := mustParse(t,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="7\.0"]`,
+		`wfn:[part="a",vendor="mozilla",product="firefox",version="8\.0"]`,
+	)
+	pattern := mustParse(t, `wfn:[part="a",version="8\.*"]`)[0]
+
+	s := NewStore()
+	assert.Equal(t, 0, s.Len())
+	s.Insert(items...)
+	old := s.Snapshot()
+	assert.ElementsMatch(t, []*Item{items[0], items[2]}, s.Query(pattern))
+
+	s.Remove(items[0])
+	assert.ElementsMatch(t, []*Item{items[2]}, s.Query(pattern))
+	assert.Equal(t, 2, s.Len())
+
+	// a snapshot is not affected by later updates.
+	assert.ElementsMatch(t, []*Item{items[0], items[2]}, old.Query(pattern))
+	assert.Equal(t, 3, old.Len())
+
+	s.Replace(items[:1])
+	assert.ElementsMatch(t, []*Item{items[0]}, s.Query(pattern))
+	assert.Equal(t, 1, s.Len())
+	assert.Equal(t, 3, old.Len())
+}
+
+func TestStoreUpdateCopiesIndex(t *testing.T) {
+	items := mustParse(t,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001"]`,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version=NA]`,
+	)
+	pattern := mustParse(t, `wfn:[part="a"]`)[0]
+
+	s := NewStore()
+	s.Insert(items...)
+	old := s.Snapshot()
+	s.Update(func(x *Index) {
+		for _, item := range items {
+			x.Remove(item)
+		}
+		x.Insert(items[0])
+	})
+
+	assert.ElementsMatch(t, items, old.Query(pattern))
+	assert.ElementsMatch(t, items[:1], s.Query(pattern))
+}
+
+func TestStoreUpdateRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewStore()
+	snapshots, expects := []*Snapshot{}, [][]*Item{}
+	items := []*Item{}
+	for i := 0; i < 200; i++ {
+		item := randomItem(r, false)
+		if r.Intn(3) == 0 {
+			s.Remove(item)
+			rest := []*Item{}
+			for _, v := range items {
+				if *v != *item {
+					rest = append(rest, v)
+				}
+			}
+			items = rest
+		} else {
+			s.Insert(item)
+			items = append(items, item)
+		}
+		snapshots = append(snapshots, s.Snapshot())
+		expects = append(expects, append([]*Item(nil), items...))
+	}
+
+	// every snapshot keeps the items at the time.
+	for i, snapshot := range snapshots {
+		assert.Equal(t, len(expects[i]), snapshot.Len(), "%d", i)
+		assert.ElementsMatch(t, expects[i], snapshot.Query(NewItem()), "%d", i)
+	}
+}
+
+func TestStoreUpdateSharesIndex(t *testing.T) {
+	items := mustParse(t,
+		`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0"]`,
+		`wfn:[part="a",vendor="mozilla",product="firefox",version="8\.0"]`,
+		`wfn:[part="a",vendor="mozilla",product="firefox",version="9\.0"]`,
+	)
+	s := NewStore()
+	s.Insert(items[:2]...)
+	old := s.Snapshot().index
+	s.Insert(items[2])
+	x := s.Snapshot().index
+
+	mozilla, microsoft := indexKey(items[1].vendor), indexKey(items[0].vendor)
+	assert.Len(t, old.parts[Application][microsoft], 1)
+	// the products of microsoft are shared, and the products of mozilla are copied.
+	assert.Equal(t, reflect.ValueOf(old.parts[Application][microsoft]).Pointer(), reflect.ValueOf(x.parts[Application][microsoft]).Pointer())
+	assert.NotEqual(t, reflect.ValueOf(old.parts[Application][mozilla]).Pointer(), reflect.ValueOf(x.parts[Application][mozilla]).Pointer())
+	assert.Same(t, old.parts[Application][microsoft][indexKey(items[0].product)], x.parts[Application][microsoft][indexKey(items[0].product)])
+	assert.NotSame(t, old.parts[Application][mozilla][indexKey(items[1].product)], x.parts[Application][mozilla][indexKey(items[1].product)])
+	// the trie shares the node of "8" and copies the root.
+	oldFirefox, firefox := old.parts[Application][mozilla][indexKey(items[1].product)], x.parts[Application][mozilla][indexKey(items[1].product)]
+	assert.Same(t, oldFirefox.versions.children['8'], firefox.versions.children['8'])
+	assert.Nil(t, oldFirefox.versions.children['9'])
+	assert.Nil(t, x.copied)
+}
+
+// TestStoreConcurrent queries a store while it is reloaded.  Run with -race.
+func TestStoreConcurrent(t *testing.T) {
+	const size = 200
+	generation := func(g int) []*Item {
+		items := make([]*Item, size)
+		for i := range items {
+			item := NewItem()
+			item.SetPart(Application)
+			item.SetVendor(NewStringAttr(fmt.Sprintf("vendor%d", i%10)))
+			item.SetProduct(NewStringAttr(fmt.Sprintf("product%d", i)))
+			item.SetUpdate(NewStringAttr(fmt.Sprintf("gen%d", g)))
+			items[i] = item
+		}
+		return items
+	}
+
+	s := NewStore()
+	s.Replace(generation(0))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pattern := NewItem()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				items := s.Query(pattern)
+				if len(items) != size {
+					t.Errorf("a query sees %d items", len(items))
+					return
+				}
+				// all items must be of the same generation.
+				for _, item := range items {
+					if item.Update() != items[0].Update() {
+						t.Errorf("a query sees %s and %s", item.Update(), items[0].Update())
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for g := 1; g <= 20; g++ {
+		if g%2 == 0 {
+			s.Replace(generation(g))
+			continue
+		}
+		next := generation(g)
+		s.Update(func(x *Index) {
+			for _, item := range s.Snapshot().Query(NewItem()) {
+				x.Remove(item)
+			}
+			for _, item := range next {
+				x.Insert(item)
+			}
+		})
+	}
+	close(done)
+	wg.Wait()
+	assert.Equal(t, size, s.Len())
+}
+
+func BenchmarkStoreUpdate(b *testing.B) {
+	items := make([]*Item, 100000)
+	for i := range items {
+		item := NewItem()
+		item.SetPart(Application)
+		item.SetVendor(NewStringAttr(fmt.Sprintf("vendor%d", i%1000)))
+		item.SetProduct(NewStringAttr(fmt.Sprintf("product%d", i%100)))
+		item.SetVersion(NewStringAttr(fmt.Sprintf("%d.%d", i%7, i)))
+		items[i] = item
+	}
+	s := NewStore()
+	s.Replace(items)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		item := items[i%len(items)]
+		s.Remove(item)
+		s.Insert(item)
+	}
+}
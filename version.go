@@ -0,0 +1,630 @@
+package cpe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VersionComparator compares version a with b.  Returns a negative number if a is older than b,
+// 0 if they are the same, and a positive number if a is newer than b.
+// Returns an error wrapping ErrInvalidVersion if a or b is not a version of the scheme.
+type VersionComparator func(a, b string) (int, error)
+
+// VersionRange reprecents a range of versions, as versionStartIncluding, versionStartExcluding,
+// versionEndIncluding and versionEndExcluding of the NVD.  An empty bound means the range is unbounded on that side.
+type VersionRange struct {
+	StartIncluding string
+	StartExcluding string
+	EndIncluding   string
+	EndExcluding   string
+	// Comparator compares versions.  CompareDottedVersions is used if it is nil.
+	Comparator VersionComparator
+}
+
+// Contains returns true if version is in r.
+func (r VersionRange) Contains(version string) (bool, error) {
+	cmp := r.Comparator
+	if cmp == nil {
+		cmp = CompareDottedVersions
+	}
+
+	for _, bound := range []struct {
+		version string
+		ok      func(int) bool
+	}{
+		{r.StartIncluding, func(c int) bool { return c >= 0 }},
+		{r.StartExcluding, func(c int) bool { return c > 0 }},
+		{r.EndIncluding, func(c int) bool { return c <= 0 }},
+		{r.EndExcluding, func(c int) bool { return c < 0 }},
+	} {
+		if bound.version == "" {
+			continue
+		}
+		c, err := cmp(version, bound.version)
+		if err != nil {
+			return false, err
+		}
+		if !bound.ok(c) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Match returns true if the version attribute of item is in r.
+// A version which is a logical value or has wildcards is not in any range.
+func (r VersionRange) Match(item *Item) (bool, error) {
+	if item.version.IsLogical() || item.version.HasWildcard() {
+		return false, nil
+	}
+	return r.Contains(item.version.literal)
+}
+
+// CompareDottedVersions compares versions which are numbers separated by ".", e.g. "2.4.10".
+// A component may have a non-numeric suffix, e.g. "1p1", which is newer than the number only.
+// Missing components are treated as 0, so "1.0" and "1" are the same.
+// Every component must begin with a digit.
+func CompareDottedVersions(a, b string) (int, error) {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	if !isDottedVersion(as) || !isDottedVersion(bs) {
+		return 0, errInvalidVersion(a, b)
+	}
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		ac, bc := "0", "0"
+		if i < len(as) {
+			ac = as[i]
+		}
+		if i < len(bs) {
+			bc = bs[i]
+		}
+
+		an, asuf := splitDigits(ac)
+		bn, bsuf := splitDigits(bc)
+		if c := compareNumbers(an, bn); c != 0 {
+			return c, nil
+		}
+		if c := strings.Compare(asuf, bsuf); c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// isDottedVersion returns true if all components begin with a digit.
+func isDottedVersion(components []string) bool {
+	for _, c := range components {
+		if c == "" || !isDigit(c[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareSemanticVersions compares versions as Semantic Versioning 2.0.0, e.g. "1.0.0-rc.1+build.5".
+// A leading "v" is allowed.  Build metadata is ignored.
+func CompareSemanticVersions(a, b string) (int, error) {
+	av, aok := parseSemanticVersion(a)
+	bv, bok := parseSemanticVersion(b)
+	if !aok || !bok {
+		return 0, errInvalidVersion(a, b)
+	}
+
+	for i := 0; i < 3; i++ {
+		if c := compareNumbers(av[i], bv[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	// a version without pre-release is newer than one with pre-release.
+	switch {
+	case av[3] == "" && bv[3] == "":
+		return 0, nil
+	case av[3] == "":
+		return 1, nil
+	case bv[3] == "":
+		return -1, nil
+	}
+
+	ap, bp := strings.Split(av[3], "."), strings.Split(bv[3], ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, bn := isDigits(ap[i]), isDigits(bp[i])
+		switch {
+		case an && bn:
+			if c := compareNumbers(ap[i], bp[i]); c != 0 {
+				return c, nil
+			}
+		case an:
+			return -1, nil
+		case bn:
+			return 1, nil
+		default:
+			if c := strings.Compare(ap[i], bp[i]); c != 0 {
+				return c, nil
+			}
+		}
+	}
+	return compareInts(len(ap), len(bp)), nil
+}
+
+// parseSemanticVersion returns major, minor, patch and pre-release of str.
+func parseSemanticVersion(str string) ([4]string, bool) {
+	v := [4]string{}
+	str = strings.TrimPrefix(str, "v")
+	if i := strings.IndexByte(str, '+'); i >= 0 {
+		if !isSemanticIdentifiers(str[i+1:], false) {
+			return v, false
+		}
+		str = str[:i]
+	}
+	if i := strings.IndexByte(str, '-'); i >= 0 {
+		v[3] = str[i+1:]
+		if !isSemanticIdentifiers(v[3], true) {
+			return v, false
+		}
+		str = str[:i]
+	}
+
+	core := strings.Split(str, ".")
+	if len(core) != 3 {
+		return v, false
+	}
+	for i, n := range core {
+		if !isDigits(n) || (len(n) > 1 && n[0] == '0') {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// isSemanticIdentifiers returns true if str is dot separated identifiers of pre-release or build metadata.
+func isSemanticIdentifiers(str string, prerelease bool) bool {
+	for _, id := range strings.Split(str, ".") {
+		if id == "" {
+			return false
+		}
+		for i := 0; i < len(id); i++ {
+			if !isAlnum(id[i]) && id[i] != '-' {
+				return false
+			}
+		}
+		if prerelease && isDigits(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareDebianVersions compares versions of Debian packages, [epoch:]upstream_version[-debian_revision], as dpkg.
+func CompareDebianVersions(a, b string) (int, error) {
+	ae, au, ar, aok := parseDebianVersion(a)
+	be, bu, br, bok := parseDebianVersion(b)
+	if !aok || !bok {
+		return 0, errInvalidVersion(a, b)
+	}
+
+	if c := compareNumbers(ae, be); c != 0 {
+		return c, nil
+	}
+	if c := compareDebianStrings(au, bu); c != 0 {
+		return c, nil
+	}
+	return compareDebianStrings(ar, br), nil
+}
+
+func parseDebianVersion(str string) (epoch, upstream, revision string, ok bool) {
+	epoch, upstream = "0", str
+	if i := strings.IndexByte(str, ':'); i >= 0 {
+		epoch, upstream = str[:i], str[i+1:]
+		if !isDigits(epoch) {
+			return "", "", "", false
+		}
+	}
+	if i := strings.LastIndexByte(upstream, '-'); i >= 0 {
+		upstream, revision = upstream[:i], upstream[i+1:]
+	}
+	if upstream == "" {
+		return "", "", "", false
+	}
+	for i := 0; i < len(str); i++ {
+		if !isAlnum(str[i]) && !strings.ContainsRune(".+-~:", rune(str[i])) {
+			return "", "", "", false
+		}
+	}
+	return epoch, upstream, revision, true
+}
+
+// compareDebianStrings implements verrevcmp of dpkg.
+func compareDebianStrings(a, b string) int {
+	// order returns the sort weight of a non-digit character: "~" sorts before anything, even the end of the part,
+	// and letters sort before non-letters.
+	order := func(s string, i int) int {
+		switch {
+		case i >= len(s) || isDigit(s[i]):
+			return 0
+		case s[i] == '~':
+			return -1
+		case isAlpha(s[i]):
+			return int(s[i])
+		}
+		return int(s[i]) + 256
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			if c := compareInts(order(a, i), order(b, j)); c != 0 {
+				return c
+			}
+			i++
+			j++
+		}
+		if i > len(a) {
+			i = len(a)
+		}
+		if j > len(b) {
+			j = len(b)
+		}
+
+		an, _ := splitDigits(a[i:])
+		bn, _ := splitDigits(b[j:])
+		if c := compareNumbers(an, bn); c != 0 {
+			return c
+		}
+		i += len(an)
+		j += len(bn)
+	}
+	return 0
+}
+
+// CompareRpmVersions compares versions of RPM packages, [epoch:]version[-release], as rpmvercmp.
+// Releases are compared only if both versions have them.
+func CompareRpmVersions(a, b string) (int, error) {
+	ae, av, ar, aok := parseRpmVersion(a)
+	be, bv, br, bok := parseRpmVersion(b)
+	if !aok || !bok {
+		return 0, errInvalidVersion(a, b)
+	}
+
+	if c := compareNumbers(ae, be); c != 0 {
+		return c, nil
+	}
+	if c := compareRpmStrings(av, bv); c != 0 {
+		return c, nil
+	}
+	if ar == "" || br == "" {
+		return 0, nil
+	}
+	return compareRpmStrings(ar, br), nil
+}
+
+func parseRpmVersion(str string) (epoch, version, release string, ok bool) {
+	epoch, version = "0", str
+	if i := strings.IndexByte(str, ':'); i >= 0 {
+		epoch, version = str[:i], str[i+1:]
+		if !isDigits(epoch) {
+			return "", "", "", false
+		}
+	}
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		version, release = version[:i], version[i+1:]
+	}
+	if version == "" {
+		return "", "", "", false
+	}
+	return epoch, version, release, true
+}
+
+// compareRpmStrings implements rpmvercmp.
+func compareRpmStrings(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	trimSeparators := func(s string) string {
+		for len(s) > 0 && !isAlnum(s[0]) && s[0] != '~' && s[0] != '^' {
+			s = s[1:]
+		}
+		return s
+	}
+	for len(a) > 0 || len(b) > 0 {
+		a, b = trimSeparators(a), trimSeparators(b)
+
+		// "~" sorts before anything, even the end of the string.
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			} else if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// "^" sorts after the end of the string, but before anything else.
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !strings.HasPrefix(a, "^"):
+				return 1
+			case !strings.HasPrefix(b, "^"):
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		var as, bs string
+		isnum := isDigit(a[0])
+		if isnum {
+			as, _ = splitDigits(a)
+			bs, _ = splitDigits(b)
+		} else {
+			as, bs = leadingAlphas(a), leadingAlphas(b)
+		}
+		a, b = a[len(as):], b[len(bs):]
+
+		if bs == "" {
+			// a numeric segment is newer than an alphabetic one.
+			if isnum {
+				return 1
+			}
+			return -1
+		}
+		if isnum {
+			if c := compareNumbers(as, bs); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(as, bs); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+func errInvalidVersion(a, b string) error {
+	return fmt.Errorf("cpe:cannot compare %q with %q: %w", a, b, ErrInvalidVersion)
+}
+
+// splitDigits splits str into the leading digits and the rest.
+func splitDigits(str string) (string, string) {
+	i := 0
+	for i < len(str) && isDigit(str[i]) {
+		i++
+	}
+	return str[:i], str[i:]
+}
+
+func leadingAlphas(str string) string {
+	i := 0
+	for i < len(str) && isAlpha(str[i]) {
+		i++
+	}
+	return str[:i]
+}
+
+// compareNumbers compares strings of decimal digits numerically.  An empty string is 0.
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := compareInts(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func isDigits(str string) bool {
+	if str == "" {
+		return false
+	}
+	_, rest := splitDigits(str)
+	return rest == ""
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// Version reprecents a version of an item, which is ordered by the version attribute and then the update attribute.
+// Versions are compared segment by segment: a run of digits is compared numerically, and is newer than a run of letters.
+// Pre-release markers, such as "alpha", "beta", "rc" or a segment after "~", are older than the release,
+// so "2.4.1-rc1" is older than "2.4.1".  A leading number followed by ":" is an epoch, e.g. "1:2.3~beta".
+type Version struct {
+	version string
+	update  string
+	epoch   string
+	tokens  []versionToken
+	updates []versionToken
+}
+
+type versionToken struct {
+	value   string
+	numeric bool
+	pre     bool // a pre-release marker
+}
+
+// preReleaseMarkers are words which mark pre-releases, from older to newer.
+// Single letters are not markers, as they often mark patch releases, e.g. "1.0.2a" of OpenSSL.
+var preReleaseMarkers = []string{"dev", "snapshot", "alpha", "beta", "preview", "pre", "rc"}
+
+// NewVersion returns the version of an item with the version attribute version and the update attribute update.
+// update is not used if it is ANY or NA.  Returns an error wrapping ErrInvalidVersion if version is a logical value,
+// or version or update has wildcards.
+func NewVersion(version, update StringAttr) (Version, error) {
+	if version.IsLogical() || version.HasWildcard() || update.HasWildcard() {
+		return Version{}, fmt.Errorf("cpe:cannot order version %q: %w", version.String(), ErrInvalidVersion)
+	}
+
+	v := Version{
+		version: version.literal,
+		update:  update.literal,
+		epoch:   "0",
+	}
+	str := strings.ToLower(version.literal)
+	if n, rest := splitDigits(str); n != "" && strings.HasPrefix(rest, ":") {
+		v.epoch, str = n, rest[1:]
+	}
+	v.tokens = splitVersion(str)
+	v.updates = splitVersion(strings.ToLower(update.literal))
+	return v, nil
+}
+
+// CompareVersions compares the versions of a and b, using update attributes as a tiebreaker.
+// Returns a negative number if a is older than b, 0 if they are the same, and a positive number if a is newer than b.
+func CompareVersions(a, b *Item) (int, error) {
+	av, err := NewVersion(a.version, a.update)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := NewVersion(b.version, b.update)
+	if err != nil {
+		return 0, err
+	}
+	return av.Compare(bv), nil
+}
+
+// CompareVersionStrings is a VersionComparator which compares unquoted version strings as Version.
+func CompareVersionStrings(a, b string) (int, error) {
+	if a == "" || b == "" {
+		return 0, errInvalidVersion(a, b)
+	}
+	av, _ := NewVersion(NewLiteralStringAttr(a), Any)
+	bv, _ := NewVersion(NewLiteralStringAttr(b), Any)
+	return av.Compare(bv), nil
+}
+
+// Compare compares v with o.  Returns a negative number if v is older than o,
+// 0 if they are the same, and a positive number if v is newer than o.
+func (v Version) Compare(o Version) int {
+	if c := compareNumbers(v.epoch, o.epoch); c != 0 {
+		return c
+	}
+	if c := compareVersionTokens(v.tokens, o.tokens); c != 0 {
+		return c
+	}
+	return compareVersionTokens(v.updates, o.updates)
+}
+
+// String returns the version and the update, e.g. "8.0.6001 sp1".
+func (v Version) String() string {
+	if v.update == "" {
+		return v.version
+	}
+	return v.version + " " + v.update
+}
+
+// splitVersion splits str into runs of digits and runs of letters.  Other characters separate them.
+func splitVersion(str string) []versionToken {
+	tokens := []versionToken{}
+	pre := false
+	for len(str) > 0 {
+		var t versionToken
+		switch {
+		case isDigit(str[0]):
+			t.value, _ = splitDigits(str)
+			t.numeric = true
+		case isAlpha(str[0]):
+			t.value = leadingAlphas(str)
+			t.pre = pre || isPreReleaseMarker(t.value)
+		default:
+			pre = str[0] == '~' || (pre && !isAlnum(str[0]))
+			str = str[1:]
+			continue
+		}
+		tokens = append(tokens, t)
+		str = str[len(t.value):]
+		pre = false
+	}
+	return tokens
+}
+
+func isPreReleaseMarker(str string) bool {
+	return preReleaseRank(str) >= 0
+}
+
+func preReleaseRank(str string) int {
+	for i, m := range preReleaseMarkers {
+		if m == str {
+			return i
+		}
+	}
+	return -1
+}
+
+func compareVersionTokens(a, b []versionToken) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var c int
+		switch {
+		case i >= len(a):
+			c = -compareMissingToken(b[i])
+		case i >= len(b):
+			c = compareMissingToken(a[i])
+		default:
+			c = compareVersionToken(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareMissingToken compares t with the end of the other version.
+func compareMissingToken(t versionToken) int {
+	switch {
+	case t.numeric:
+		// "1.0" and "1" are the same.
+		return compareNumbers(t.value, "")
+	case t.pre:
+		return -1
+	}
+	return 1
+}
+
+func compareVersionToken(a, b versionToken) int {
+	switch {
+	case a.numeric && b.numeric:
+		return compareNumbers(a.value, b.value)
+	case a.numeric:
+		return 1
+	case b.numeric:
+		return -1
+	case a.pre && b.pre:
+		if c := compareInts(preReleaseRank(a.value), preReleaseRank(b.value)); c != 0 {
+			return c
+		}
+	case a.pre:
+		return -1
+	case b.pre:
+		return 1
+	}
+	return strings.Compare(a.value, b.value)
+}
@@ -0,0 +1,275 @@
+package cpe
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type versionTestcase struct {
+	a      string
+	b      string
+	expect int
+}
+
+func testVersionComparator(t *testing.T, cmp VersionComparator, cases []versionTestcase) {
+	for i, c := range cases {
+		r, err := cmp(c.a, c.b)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, sign(r), "%d: %s %s", i, c.a, c.b)
+
+		r, err = cmp(c.b, c.a)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, -c.expect, sign(r), "%d: %s %s", i, c.b, c.a)
+	}
+}
+
+func sign(n int) int {
+	return compareInts(n, 0)
+}
+
+func TestCompareDottedVersions(t *testing.T) {
+	testVersionComparator(t, CompareDottedVersions, []versionTestcase{
+		{"1.0", "1.0", 0},
+		{"1.0", "1", 0},
+		{"1.0.0", "1.0.1", -1},
+		{"2.4.10", "2.4.9", 1},
+		{"10.0", "9.99", 1},
+		{"1.02", "1.2", 0},
+		{"7.4p1", "7.4", 1},
+		{"7.4p1", "7.4p2", -1},
+		{"1.0.1", "1.0.1a", -1},
+		{"18446744073709551616", "18446744073709551615", 1},
+	})
+
+	for i, v := range []string{"", "x", "y", "1..2", ".", "1.", ".1", "1.x", "v1.0"} {
+		_, err := CompareDottedVersions(v, "1.0")
+		assert.True(t, errors.Is(err, ErrInvalidVersion), "%d: %q", i, v)
+		_, err = CompareDottedVersions("1.0", v)
+		assert.True(t, errors.Is(err, ErrInvalidVersion), "%d: %q", i, v)
+	}
+}
+
+func TestCompareSemanticVersions(t *testing.T) {
+	// examples of Semantic Versioning 2.0.0 11.
+	testVersionComparator(t, CompareSemanticVersions, []versionTestcase{
+		{"1.0.0", "2.0.0", -1},
+		{"2.0.0", "2.1.0", -1},
+		{"2.1.0", "2.1.1", -1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta", "1.0.0-beta.2", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"v1.2.3", "1.2.3", 0},
+	})
+
+	for i, v := range []string{"1.0", "1.0.0.0", "01.0.0", "1.0.0-", "1.0.0-01", "1.0.0+", "1.0.0-a..b", "1.0.0-a_b", "a.b.c"} {
+		_, err := CompareSemanticVersions(v, "1.0.0")
+		assert.True(t, errors.Is(err, ErrInvalidVersion), "%d: %s", i, v)
+	}
+}
+
+func TestCompareDebianVersions(t *testing.T) {
+	testVersionComparator(t, CompareDebianVersions, []versionTestcase{
+		{"1.0", "1.0", 0},
+		{"1.0-1", "1.0-2", -1},
+		{"1.0-1", "1.0-1ubuntu1", -1},
+		{"1:1.0", "2.0", 1},
+		{"0:1.0", "1.0", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0a", "1.0+", -1},
+		{"1.0.10", "1.0.9", 1},
+		{"2.7.4+dfsg-1", "2.7.4-1", 1},
+		{"1.2.3-4-5", "1.2.3-4-4", 1},
+	})
+
+	for i, v := range []string{"", "a:1.0", "1:", "-1", "1.0 beta"} {
+		_, err := CompareDebianVersions(v, "1.0")
+		assert.True(t, errors.Is(err, ErrInvalidVersion), "%d: %s", i, v)
+	}
+}
+
+func TestCompareRpmVersions(t *testing.T) {
+	// examples of rpmvercmp tests.
+	testVersionComparator(t, CompareRpmVersions, []versionTestcase{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0.1a", -1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"1.0aa", "1.0a", 1},
+		{"1.0", "1.0a", -1},
+		{"2.0", "2_0", 0},
+		{"2.0a", "2.0.a", 0},
+		{"6.0.rc1", "6.0", 1},
+		{"10b2", "10a1", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0.1", -1},
+		{"1.0^git1", "1.0~rc1", 1},
+		{"1:1.0", "2.0", 1},
+		{"1.0-1", "1.0-2", -1},
+		{"1.0-1", "1.0", 0},
+		{"1.0é1", "1.0.1", 0},
+	})
+
+	for i, v := range []string{"", "a:1.0", "1:-1"} {
+		_, err := CompareRpmVersions(v, "1.0")
+		assert.True(t, errors.Is(err, ErrInvalidVersion), "%d: %s", i, v)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	type testcase struct {
+		r       VersionRange
+		version string
+		expect  bool
+	}
+	var cases = []testcase{
+		{VersionRange{}, "1.0", true},
+		{VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.10"}, "2.4.0", true},
+		{VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.10"}, "2.4.9", true},
+		{VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.10"}, "2.4.10", false},
+		{VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.10"}, "2.3.99", false},
+		{VersionRange{StartExcluding: "2.4.0", EndIncluding: "2.4.10"}, "2.4.0", false},
+		{VersionRange{StartExcluding: "2.4.0", EndIncluding: "2.4.10"}, "2.4.10", true},
+		{VersionRange{EndExcluding: "1.0.0", Comparator: CompareSemanticVersions}, "1.0.0-rc.1", true},
+		{VersionRange{EndExcluding: "1:2.0-1", Comparator: CompareDebianVersions}, "2.0-1", true},
+		{VersionRange{EndExcluding: "1:2.0-1", Comparator: CompareDebianVersions}, "1:2.0-1", false},
+		{VersionRange{StartIncluding: "1.0^", Comparator: CompareRpmVersions}, "1.0", false},
+	}
+
+	for i, c := range cases {
+		ok, err := c.r.Contains(c.version)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, ok, "%d", i)
+	}
+
+	_, err := VersionRange{EndExcluding: "1.0.0", Comparator: CompareSemanticVersions}.Contains("1.0")
+	assert.True(t, errors.Is(err, ErrInvalidVersion))
+}
+
+func TestVersionRangeMatch(t *testing.T) {
+	r := VersionRange{StartIncluding: "8.0", EndExcluding: "9.0"}
+	type testcase struct {
+		wfn    string
+		expect bool
+	}
+	var cases = []testcase{
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.0\.6001"]`, true},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="9\.0"]`, false},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version="8\.*"]`, false},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer",version=NA]`, false},
+		{`wfn:[part="a",vendor="microsoft",product="internet_explorer"]`, false},
+	}
+
+	for i, c := range cases {
+		item, err := NewItemFromWfn(c.wfn)
+		assert.Nil(t, err, "%d", i)
+		ok, err := r.Match(item)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, ok, "%d", i)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	type testcase struct {
+		a      string
+		b      string
+		expect int
+	}
+	var cases = []testcase{
+		{`wfn:[version="8\.0\.6001"]`, `wfn:[version="8\.0\.6001"]`, 0},
+		{`wfn:[version="8\.0\.6001"]`, `wfn:[version="8\.0\.6000"]`, 1},
+		{`wfn:[version="8\.0"]`, `wfn:[version="8"]`, 0},
+		{`wfn:[version="10\.0"]`, `wfn:[version="9\.9"]`, 1},
+		{`wfn:[version="2\.4\.1\-rc1"]`, `wfn:[version="2\.4\.1"]`, -1},
+		{`wfn:[version="2\.4\.1\-rc1"]`, `wfn:[version="2\.4\.1\-rc2"]`, -1},
+		{`wfn:[version="2\.4\.1\-beta"]`, `wfn:[version="2\.4\.1\-RC1"]`, -1},
+		{`wfn:[version="2\.4\.1\-alpha"]`, `wfn:[version="2\.4\.1\-beta"]`, -1},
+		{`wfn:[version="2\.4\.1"]`, `wfn:[version="2\.4\.1a"]`, -1},
+		{`wfn:[version="2\.4\.1a"]`, `wfn:[version="2\.4\.1\.1"]`, -1},
+		{`wfn:[version="1\:2\.3\~beta"]`, `wfn:[version="2\.3"]`, 1},
+		{`wfn:[version="1\:2\.3\~beta"]`, `wfn:[version="1\:2\.3"]`, -1},
+		{`wfn:[version="1\:2\.3\~beta"]`, `wfn:[version="1\:2\.3\~rc"]`, -1},
+		{`wfn:[version="2\.3\~xyz"]`, `wfn:[version="2\.3"]`, -1},
+		{`wfn:[version="7\.4p1"]`, `wfn:[version="7\.4"]`, 1},
+		{`wfn:[version="2000",update="sp1"]`, `wfn:[version="2000"]`, 1},
+		{`wfn:[version="2000",update="sp1"]`, `wfn:[version="2000",update="sp2"]`, -1},
+		{`wfn:[version="2000",update=NA]`, `wfn:[version="2000"]`, 0},
+		{`wfn:[version="1\.8\.0",update="update_45"]`, `wfn:[version="1\.8\.0",update="update_101"]`, -1},
+		{`wfn:[version="1\.8\.0",update="beta"]`, `wfn:[version="1\.8\.0"]`, -1},
+		{`wfn:[version="1\.8\.1",update="beta"]`, `wfn:[version="1\.8\.0",update="update_101"]`, 1},
+	}
+
+	for i, c := range cases {
+		a, err := NewItemFromWfn(c.a)
+		assert.Nil(t, err, "%d", i)
+		b, err := NewItemFromWfn(c.b)
+		assert.Nil(t, err, "%d", i)
+
+		r, err := CompareVersions(a, b)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, sign(r), "%d: %s %s", i, c.a, c.b)
+		r, err = CompareVersions(b, a)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, -c.expect, sign(r), "%d: %s %s", i, c.b, c.a)
+	}
+
+	for i, wfn := range []string{`wfn:[version=NA]`, `wfn:[]`, `wfn:[version="8\.*"]`, `wfn:[version="8",update="sp?"]`} {
+		item, err := NewItemFromWfn(wfn)
+		assert.Nil(t, err, "%d", i)
+		_, err = CompareVersions(item, item)
+		assert.True(t, errors.Is(err, ErrInvalidVersion), "%d", i)
+	}
+}
+
+func TestVersionSort(t *testing.T) {
+	versions := []Version{}
+	for _, v := range [][2]string{
+		{"2.4.1", ""}, {"2.4.1", "sp1"}, {"2.4.1-rc1", ""}, {"2.4.10", ""}, {"2.4.1~beta", ""}, {"2.4.9", ""}, {"1:1.0", ""},
+	} {
+		version, err := NewVersion(NewLiteralStringAttr(v[0]), NewLiteralStringAttr(v[1]))
+		assert.Nil(t, err)
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) < 0
+	})
+
+	sorted := []string{}
+	for _, v := range versions {
+		sorted = append(sorted, v.String())
+	}
+	assert.Equal(t, []string{"2.4.1~beta", "2.4.1-rc1", "2.4.1", "2.4.1 sp1", "2.4.9", "2.4.10", "1:1.0"}, sorted)
+}
+
+func TestCompareVersionStrings(t *testing.T) {
+	r := VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.1", Comparator: CompareVersionStrings}
+	for i, c := range []struct {
+		version string
+		expect  bool
+	}{
+		{"2.4.0", true},
+		{"2.4.1-rc1", true},
+		{"2.4.1", false},
+		{"2.4.0-rc1", false},
+	} {
+		ok, err := r.Contains(c.version)
+		assert.Nil(t, err, "%d", i)
+		assert.Equal(t, c.expect, ok, "%d", i)
+	}
+}